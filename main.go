@@ -1,5 +1,85 @@
 /* Pulp CLI
  *
+ * - Version 1.26.0 - 2026/10/19
+ *     The search command finds packages by name, NEVRA, sha256, capability
+ *     or file and shows the repositories and distributions holding them.
+ * - Version 1.25.0 - 2026/10/19
+ *     list -p lists a given version or environment, filters by name, arch or
+ *     newest only, and shows the size and build time, sorted by NEVRA.
+ * - Version 1.24.0 - 2026/10/19
+ *     The diff command compares the packages of two repository versions or
+ *     distributions, set shows that difference and asks for confirmation.
+ * - Version 1.23.0 - 2026/10/19
+ *     The copy command copies packages or advisories, optionally with their
+ *     dependencies, from a version of one repository into another.
+ * - Version 1.22.0 - 2026/10/19
+ *     The label command sets, unsets and shows labels of repositories,
+ *     distributions and remotes, list -l selects repositories by label.
+ * - Version 1.21.0 - 2026/10/19
+ *     Publications use the checksum, gpgcheck and sqlite settings of the
+ *     repository, the new publish command overrides them with flags.
+ * - Version 1.20.0 - 2026/10/19
+ *     The remote command creates, shows, updates, deletes and lists remotes
+ *     and attaches them to or detaches them from repositories.
+ * - Version 1.19.0 - 2026/10/19
+ *     The repo create and repo delete commands set up and tear down a
+ *     repository with its remote and the distributions of all environments.
+ * - Version 1.18.0 - 2026/10/19
+ *     The prune command removes the publications and repository versions
+ *     no distribution serves, keeping the newest ones and the latest, as
+ *     many as -keep or the repository's retain_repo_versions.
+ * - Version 1.17.0 - 2026/10/19
+ *     The prune-packages command keeps only the newest versions of each
+ *     package, following -k or a per repository retain_packages setting.
+ * - Version 1.16.0 - 2026/10/19
+ *     Packages can be deleted by name, NEVRA or glob. All matches are shown
+ *     for confirmation (-y) and removed in a single repository version.
+ * - Version 1.15.0 - 2026/10/19
+ *     Artifacts and content units are verified against the local package
+ *     after upload, a repository version lacking packages is rolled back.
+ * - Version 1.14.0 - 2026/10/19
+ *     Header and payload digests of packages are verified before upload, as
+ *     well as their arch and dist tag against the repository (-force).
+ * - Version 1.13.0 - 2026/10/19
+ *     A comps.xml can be uploaded into a repository, its groups, categories
+ *     and environments listed and package groups removed.
+ * - Version 1.12.0 - 2026/10/19
+ *     Advisories (errata) can be uploaded from updateinfo xml or json, listed,
+ *     shown and removed, or added together with their packages.
+ * - Version 1.11.0 - 2026/10/19
+ *     Source packages can be added, deleted, synced (-with-srpm) and listed
+ *     separately from binary packages. Repositories may have arch 'src'.
+ * - Version 1.10.0 - 2026/10/19
+ *     Packages already known to Pulp are added to other repositories by
+ *     reusing their artifact or content unit, instead of failing.
+ * - Version 1.9.0 - 2026/10/19
+ *     Package signatures can be verified against a keyring of trusted keys,
+ *     following a default or per repository signature policy.
+ * - Version 1.8.0 - 2026/10/19
+ *     Packages are identified by reading their RPM header instead of guessing
+ *     from the filename. Invalid packages are rejected before upload.
+ * - Version 1.7.0 - 2026/10/19
+ *     Added a progress display with throughput and ETA for hashing, uploads
+ *     and downloads, and with the progress reports of running tasks.
+ * - Version 1.6.0 - 2026/10/19
+ *     Chunks are uploaded by a worker pool that retries failed chunks with
+ *     backoff and stops at the first fatal error. The chunksize and number
+ *     of workers can be configured with 'config'.
+ * - Version 1.5.0 - 2026/10/19
+ *     Uploads are streamed instead of being buffered in memory and the
+ *     checksum of a package is calculated while reading its header, small
+ *     packages uploaded in one piece are hashed again to detect changes.
+ * - Version 1.4.0 - 2026/10/19
+ *     Chunked uploads can be resumed. Their state is kept in ~/.pulp/uploads
+ *     and can be managed with the new 'upload list' and 'upload abort'
+ *     subcommands.
+ * - Version 1.3.0 - 2026/10/19
+ *     The 'add' subcommand accepts absolute paths, http(s) urls and stdin.
+ *     Downloaded packages are verified against an optional sha256 checksum.
+ * - Version 1.2.0 - 2026/10/19
+ *     The 'add' subcommand accepts multiple packages, globs and directories.
+ *     Packages are uploaded concurrently and end up in a single repository
+ *     version, which is published and distributed only once.
  * - version 1.1.4 - 2021/11/09
  *     Fixed bug that prevented all versions of a repo from being show, because
  *     the code did not iterate through all publications.
@@ -27,23 +107,31 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
 
 const (
-	VERSION        string        = "1.1.4"
-	API_ENDPOINT   string        = "/pulp/api/v3"
-	CLIENT_TIMEOUT time.Duration = 300
-	CHUNKSIZE      int64         = 8388608 // Pulp3 Nginx limits uploads to 10Mb, so chunk sizes must be lower than that.
-	MAX_THREADS    int           = 10
+	VERSION           string        = "1.26.0"
+	API_ENDPOINT      string        = "/pulp/api/v3"
+	CLIENT_TIMEOUT    time.Duration = 300
+	CHUNKSIZE         int64         = 8388608 // Pulp3 Nginx limits uploads to 10Mb, so chunk sizes must be lower than that.
+	MAX_THREADS       int           = 10
+	MAX_RETRIES       int           = 5 // Number of attempts for uploading a single chunk.
+	RETRY_BACKOFF     time.Duration = 2 * time.Second
+	PROGRESS_INTERVAL time.Duration = 10 * time.Second // Time between progress lines when not on a terminal.
+	MAX_UPLOADS       int           = 4                // Number of packages uploaded concurrently by 'add'.
 )
 
-// API user, password, server, endpoint, environments and client connection
+// API user, password, server, endpoint, environments, client connection, upload tuning and configuration
 var (
 	apiUser, apiPass, apiSrv, apiEnd string
 	apiEnv                           = [...]string{"dev", "uat", "oat", "prd"} // The first element is the default
 	apiClt                           *http.Client
+	apiChunk                         = CHUNKSIZE
+	apiThreads                       = MAX_THREADS
+	apiCfg                           Configuration
 )
 
 func main() {
@@ -56,24 +144,131 @@ func main() {
 	configCmd := flag.NewFlagSet("config", flag.ExitOnError)
 	configUsr := configCmd.String("u", "", "User performing administration on Pulp.")
 	configPss := configCmd.String("p", "", "Password of user performing administration.")
+	configChk := configCmd.Int64("c", 0, "Size in bytes of the chunks used for uploading large packages.")
+	configThr := configCmd.Int("t", 0, "Number of chunks uploaded in parallel.")
 
 	addCmd := flag.NewFlagSet("add", flag.ExitOnError)
 	addRep := addCmd.String("r", "", "The repository to work upon.")
+	addNam := addCmd.String("n", "", "The filename of a package read from an url or stdin.")
+	addSum := addCmd.String("s", "", "The expected sha256 checksum of a package read from an url or stdin.")
+	addAdv := addCmd.String("a", "", "An updateinfo xml or json file with advisories to add together with the packages.")
+	addFrc := addCmd.Bool("force", false, "Add packages with bad digests or an unfitting arch or dist tag anyway.")
 
 	delCmd := flag.NewFlagSet("del", flag.ExitOnError)
 	delRep := delCmd.String("r", "", "The repository to work upon.")
 	delVer := delCmd.String("v", "", "The publication version to remove.")
+	delYes := delCmd.Bool("y", false, "Remove the matching packages without asking for confirmation.")
 
 	listCmd := flag.NewFlagSet("list", flag.ExitOnError)
 	listDis := listCmd.Bool("d", false, "Display the active publication for each distribution of the given repository.")
 	listPub := listCmd.Bool("v", false, "Display all publications (versions) for the given repository.")
+	listPkg := listCmd.Bool("p", false, "Display the packages in the latest version of the given repository.")
+	listTyp := listCmd.String("t", "", "Only display binary (bin) or source (src) packages.")
+	listLbl := listCmd.String("l", "", "Only display the repositories matching a label selector, e.g. team=payments,tier!=legacy.")
+	listVer := listCmd.Int("V", -1, "Display the packages of this version of the repository, the latest one by default.")
+	listEnv := listCmd.String("e", "", "Display the packages the distribution of this environment serves.")
+	listNam := listCmd.String("n", "", "Only display the packages whose name matches this glob.")
+	listArc := listCmd.String("a", "", "Only display the packages of this arch.")
+	listNew := listCmd.Bool("N", false, "Only display the newest version of each package.")
 
 	setCmd := flag.NewFlagSet("set", flag.ExitOnError)
 	setVer := setCmd.Int("v", 0, "Set the version of the publication you want to use for the given distribution.")
+	setYes := setCmd.Bool("y", false, "Set the version without asking for confirmation.")
+
+	diffCmd := flag.NewFlagSet("diff", flag.ExitOnError)
+	diffJsn := diffCmd.Bool("json", false, "Output the differences as json.")
 
 	cleanCmd := flag.NewFlagSet("clean", flag.ExitOnError)
 
+	publishCmd := flag.NewFlagSet("publish", flag.ExitOnError)
+	publishOpt := addPublishFlags(publishCmd)
+
+	pruneCmd := flag.NewFlagSet("prune-packages", flag.ExitOnError)
+	pruneKeep := pruneCmd.Int("k", 0, "The number of versions of each package to keep.")
+	pruneDry := pruneCmd.Bool("n", false, "Only show which packages would be removed.")
+	pruneYes := pruneCmd.Bool("y", false, "Remove the packages without asking for confirmation.")
+	pruneSrv := pruneCmd.Bool("server", false, "Also let Pulp retain that many versions in new repository versions.")
+
+	pruneRepoCmd := flag.NewFlagSet("prune", flag.ExitOnError)
+	pruneRepoRep := pruneRepoCmd.String("r", "", "The repository to work upon.")
+	pruneRepoKeep := pruneRepoCmd.Int("keep", 0, "The number of repository versions to keep, by default the repository's retain_repo_versions.")
+	pruneRepoAge := pruneRepoCmd.String("older-than", "", "Only remove repository versions older than this, e.g. 30d.")
+	pruneRepoDry := pruneRepoCmd.Bool("n", false, "Only show what would be removed.")
+	pruneRepoYes := pruneRepoCmd.Bool("y", false, "Remove without asking for confirmation.")
+
+	copyCmd := flag.NewFlagSet("copy", flag.ExitOnError)
+	copyFrom := copyCmd.String("from", "", "The repository to copy from.")
+	copyVer := copyCmd.Int("v", -1, "The version of the repository to copy from, the latest one by default.")
+	copyTo := copyCmd.String("to", "", "The repository to copy to.")
+	copyAdv := copyCmd.Bool("a", false, "The arguments are advisory ids instead of packages.")
+	copyDep := copyCmd.Bool("deps", false, "Also copy the dependencies of the packages.")
+
+	searchCmd := flag.NewFlagSet("search", flag.ExitOnError)
+	searchPrv := searchCmd.Bool("provides", false, "Search the packages providing a capability.")
+	searchFil := searchCmd.Bool("file", false, "Search the packages holding a file.")
+
 	syncCmd := flag.NewFlagSet("sync", flag.ExitOnError)
+	syncSrc := syncCmd.Bool("with-srpm", false, "Also synchronize source packages.")
+
+	uploadListCmd := flag.NewFlagSet("upload list", flag.ExitOnError)
+
+	uploadAbortCmd := flag.NewFlagSet("upload abort", flag.ExitOnError)
+	uploadAll := uploadAbortCmd.Bool("a", false, "Abort all pending uploads.")
+
+	advisoryAddCmd := flag.NewFlagSet("advisory add", flag.ExitOnError)
+	advisoryAddRep := advisoryAddCmd.String("r", "", "The repository to work upon.")
+
+	advisoryListCmd := flag.NewFlagSet("advisory list", flag.ExitOnError)
+
+	advisoryShowCmd := flag.NewFlagSet("advisory show", flag.ExitOnError)
+	advisoryShowRep := advisoryShowCmd.String("r", "", "The repository to work upon.")
+
+	advisoryDelCmd := flag.NewFlagSet("advisory del", flag.ExitOnError)
+	advisoryDelRep := advisoryDelCmd.String("r", "", "The repository to work upon.")
+
+	compsAddCmd := flag.NewFlagSet("comps add", flag.ExitOnError)
+	compsAddRep := compsAddCmd.String("r", "", "The repository to work upon.")
+	compsAddRpl := compsAddCmd.Bool("replace", false, "Replace all comps content of the repository.")
+
+	compsListCmd := flag.NewFlagSet("comps list", flag.ExitOnError)
+	compsListVer := compsListCmd.Int("v", -1, "The repository version to list, the latest one by default.")
+
+	compsDelCmd := flag.NewFlagSet("comps del", flag.ExitOnError)
+	compsDelRep := compsDelCmd.String("r", "", "The repository to work upon.")
+
+	repoCreateCmd := flag.NewFlagSet("repo create", flag.ExitOnError)
+	repoCreateDsc := repoCreateCmd.String("d", "", "A description of the repository.")
+	repoCreateRem := repoCreateCmd.String("remote", "", "The url of a remote to sync the repository from.")
+
+	repoDeleteCmd := flag.NewFlagSet("repo delete", flag.ExitOnError)
+	repoDeleteYes := repoDeleteCmd.Bool("y", false, "Delete the repository without asking for confirmation.")
+
+	remoteCreateCmd := flag.NewFlagSet("remote create", flag.ExitOnError)
+	remoteCreateOpt := addRemoteFlags(remoteCreateCmd)
+
+	remoteUpdateCmd := flag.NewFlagSet("remote update", flag.ExitOnError)
+	remoteUpdateUrl := remoteUpdateCmd.String("url", "", "The new url of the remote.")
+	remoteUpdateOpt := addRemoteFlags(remoteUpdateCmd)
+
+	remoteShowCmd := flag.NewFlagSet("remote show", flag.ExitOnError)
+
+	remoteListCmd := flag.NewFlagSet("remote list", flag.ExitOnError)
+
+	remoteDeleteCmd := flag.NewFlagSet("remote delete", flag.ExitOnError)
+	remoteDeleteYes := remoteDeleteCmd.Bool("y", false, "Delete the remote without asking for confirmation.")
+
+	remoteAttachCmd := flag.NewFlagSet("remote attach", flag.ExitOnError)
+
+	remoteDetachCmd := flag.NewFlagSet("remote detach", flag.ExitOnError)
+
+	labelSetCmd := flag.NewFlagSet("label set", flag.ExitOnError)
+	labelSetTyp := labelSetCmd.String("t", "repository", "The kind of object: repository, distribution or remote.")
+
+	labelUnsetCmd := flag.NewFlagSet("label unset", flag.ExitOnError)
+	labelUnsetTyp := labelUnsetCmd.String("t", "repository", "The kind of object: repository, distribution or remote.")
+
+	labelShowCmd := flag.NewFlagSet("label show", flag.ExitOnError)
+	labelShowTyp := labelShowCmd.String("t", "repository", "The kind of object: repository, distribution or remote.")
 
 	versionCmd := flag.NewFlagSet("version", flag.ExitOnError)
 
@@ -94,6 +289,10 @@ func main() {
 			usage()
 			os.Exit(1)
 		}
+		if *configChk < 0 || *configThr < 0 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -c and -t options require a positive number!\n")
+			os.Exit(1)
+		}
 		temp := configCmd.Args()
 		pulpUrl, err := url.ParseRequestURI(temp[0])
 		if err != nil {
@@ -113,7 +312,7 @@ func main() {
 		}
 		fmt.Fprintf(flag.CommandLine.Output(), "Pulp API Status Check: %d\n", status)
 		if status == http.StatusOK {
-			adminpath, err := setAuthorization()
+			adminpath, err := setAuthorization(*configChk, *configThr)
 			if err != nil {
 				fmt.Printf("ERROR %s\n", err.Error())
 				os.Exit(1)
@@ -129,17 +328,24 @@ func main() {
 			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -r option is required for the 'add' subcommand!\n")
 			os.Exit(1)
 		}
-		if len(addCmd.Args()) != 1 {
-			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'add' subcommand requires exactly one rpm package as argument!\n")
+		if len(addCmd.Args()) == 0 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'add' subcommand requires at least one rpm package, glob, directory, url or '-' as argument!\n")
 			usage()
 			os.Exit(1)
 		}
-		temp := addCmd.Args()
-		pack := strings.TrimSpace(temp[0])
-		if filepath.Ext(pack) != ".rpm" {
-			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'add' subcommand requires exactly one rpm package as argument!\n")
+		packs, err := resolvePackages(addCmd.Args(), strings.TrimSpace(*addNam), strings.TrimSpace(*addSum))
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
 			os.Exit(1)
 		}
+		var advisories []Advisory
+		if len(*addAdv) > 0 {
+			advisories, err = readAdvisories(strings.TrimSpace(*addAdv))
+			if err != nil {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+				os.Exit(1)
+			}
+		}
 		err = getAuthorization()
 		if err != nil {
 			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
@@ -160,11 +366,30 @@ func main() {
 			fmt.Printf("ERROR %s\n", err.Error())
 			os.Exit(1)
 		}
-		err = pulpAddPackage(*addRep, pack)
+		results, err := pulpAddPackages(*addRep, packs, advisories, *addFrc)
+		summary := make(map[string]int)
+		for _, r := range results {
+			summary[r.Status]++
+			if r.Err != nil {
+				fmt.Printf("%s\t%s\t%s\n", r.Status, r.File, r.Err.Error())
+			} else if r.Signer != "" {
+				fmt.Printf("%s\t%s\t%s\tsigned by %s\n", r.Status, r.File, r.Nevra, r.Signer)
+			} else {
+				fmt.Printf("%s\t%s\t%s\n", r.Status, r.File, r.Nevra)
+			}
+		}
+		fmt.Printf("%d uploaded, %d reused, %d already present, %d failed\n", summary["uploaded"], summary["reused"], summary["already present"], summary["failed"])
 		if err != nil {
 			fmt.Printf("ERROR %s\n", err.Error())
 			os.Exit(1)
 		}
+		if summary["uploaded"]+summary["reused"] == 0 && len(advisories) == 0 {
+			fmt.Printf("Repository %s is already up to date.\n", *addRep)
+			if summary["failed"] > 0 {
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
 		pub, err := pulpPublishPackage(*addRep)
 		if err != nil {
 			fmt.Printf("ERROR %s\n", err.Error())
@@ -175,6 +400,9 @@ func main() {
 			fmt.Printf("ERROR %s\n", err.Error())
 			os.Exit(1)
 		}
+		if summary["failed"] > 0 {
+			os.Exit(1)
+		}
 	case "del":
 		delCmd.Parse(os.Args[2:])
 		if len(*delRep) == 0 && len(*delVer) == 0 {
@@ -187,8 +415,13 @@ func main() {
 			usage()
 			os.Exit(1)
 		}
-		if len(delCmd.Args()) != 1 {
-			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'del' subcommand requires exactly one argument!\n")
+		if len(*delVer) != 0 && len(delCmd.Args()) != 1 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'del' subcommand with the -v option requires exactly one repository as argument!\n")
+			usage()
+			os.Exit(1)
+		}
+		if len(*delRep) != 0 && len(delCmd.Args()) == 0 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'del' subcommand with the -r option requires at least one rpm package, NEVRA or glob as argument!\n")
 			usage()
 			os.Exit(1)
 		}
@@ -211,17 +444,30 @@ func main() {
 		temp := delCmd.Args()
 		argu := strings.TrimSpace(temp[0])
 		if len(*delRep) != 0 {
-			if filepath.Ext(argu) != ".rpm" {
-				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -r option requires a rpm package as argument!\n")
-				usage()
+			err = pulpVerifyRepo(*delRep)
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
 				os.Exit(1)
 			}
-			err = pulpVerifyRepo(*delRep)
+			var patterns []string
+			for _, arg := range temp {
+				patterns = append(patterns, strings.TrimSpace(arg))
+			}
+			packs, err := pulpResolvePackages(*delRep, patterns)
 			if err != nil {
 				fmt.Printf("ERROR %s\n", err.Error())
 				os.Exit(1)
 			}
-			err = pulpDelPackage(*delRep, argu)
+			var remove []string
+			for _, pack := range packs {
+				fmt.Printf("%s\n", packageNevra(pack))
+				remove = append(remove, pack.Pulp_href)
+			}
+			if !*delYes && !confirm(fmt.Sprintf("Remove these %d packages from repository %s?", len(packs), *delRep)) {
+				fmt.Printf("Nothing removed.\n")
+				return
+			}
+			err = pulpRemoveContentsFromRepo(*delRep, remove)
 			if err != nil {
 				fmt.Printf("ERROR %s\n", err.Error())
 				os.Exit(1)
@@ -278,20 +524,52 @@ func main() {
 		}
 	case "list":
 		listCmd.Parse(os.Args[2:])
-		if !*listPub && !*listDis {
+		if !*listPub && !*listDis && !*listPkg {
 			if len(listCmd.Args()) > 0 {
 				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: 'list' subcommand without options, does not require an argument!\n")
 				usage()
 				os.Exit(1)
 			}
 		}
-		if *listPub || *listDis {
+		if *listPub || *listDis || *listPkg {
 			if len(listCmd.Args()) != 1 {
-				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: 'list' subcommand with -d or -v options requires a repository as argument!\n")
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: 'list' subcommand with -d, -v or -p options requires a repository as argument!\n")
 				usage()
 				os.Exit(1)
 			}
 		}
+		if *listLbl != "" && (*listPub || *listDis || *listPkg) {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -l option can not be combined with the -d, -v or -p options!\n")
+			usage()
+			os.Exit(1)
+		}
+		if *listTyp != "" && (!*listPkg || (*listTyp != "bin" && *listTyp != "src")) {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -t option requires the -p option and either 'bin' or 'src'!\n")
+			usage()
+			os.Exit(1)
+		}
+		if !*listPkg && (*listVer >= 0 || *listEnv != "" || *listNam != "" || *listArc != "" || *listNew) {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -V, -e, -n, -a and -N options require the -p option!\n")
+			usage()
+			os.Exit(1)
+		}
+		if *listVer >= 0 && *listEnv != "" {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -V and -e options can not be combined!\n")
+			usage()
+			os.Exit(1)
+		}
+		if *listEnv != "" {
+			known := false
+			for _, env := range apiEnv {
+				if *listEnv == env {
+					known = true
+				}
+			}
+			if !known {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -e option requires one of the environments %s!\n", strings.Join(apiEnv[:], ", "))
+				os.Exit(1)
+			}
+		}
 		err = getAuthorization()
 		if err != nil {
 			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
@@ -322,7 +600,7 @@ func main() {
 			}
 			for _, pub := range res {
 				version := path.Base(pub.Repository_version)
-				fmt.Printf("%s\t%s\t%s\n", repo, version, pub.Pulp_created)
+				fmt.Printf("%s\t%s\t%s\tchecksum=%s/%s\tgpgcheck=%d\trepo_gpgcheck=%d\tsqlite=%t\n", repo, version, pub.Pulp_created, pub.Metadata_checksum_type, pub.Package_checksum_type, pub.Gpgcheck, pub.Repo_gpgcheck, pub.Sqlite_metadata)
 			}
 		} else if *listDis {
 			temp := listCmd.Args()
@@ -341,15 +619,61 @@ func main() {
 				version := path.Base(r.ActivePublication.Repository_version)
 				fmt.Printf("%s\t%s\t%s\n", r.Distribution, version, r.ActivePublication.Pulp_created)
 			}
+		} else if *listPkg {
+			temp := listCmd.Args()
+			repo := strings.TrimSpace(temp[0])
+			repoInfo, err := pulpRepositoryInfo(repo)
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+			if repoInfo.Count == 0 {
+				fmt.Printf("ERROR repository %s does not exist!\n", repo)
+				os.Exit(1)
+			}
+			version := repoInfo.Results[0].Latest_version_href
+			if *listVer >= 0 {
+				version, err = pulpVersionHref(repoInfo.Results[0], *listVer)
+				if err != nil {
+					fmt.Printf("ERROR %s\n", err.Error())
+					os.Exit(1)
+				}
+			}
+			if *listEnv != "" {
+				version, err = pulpDistributionVersion(repo + "-" + *listEnv)
+				if err != nil {
+					fmt.Printf("ERROR %s\n", err.Error())
+					os.Exit(1)
+				}
+				if version == "" {
+					fmt.Printf("ERROR distribution %s-%s serves nothing!\n", repo, *listEnv)
+					os.Exit(1)
+				}
+			}
+			filter := PackageFilter{
+				Kind:   *listTyp,
+				Name:   strings.TrimSpace(*listNam),
+				Arch:   strings.TrimSpace(*listArc),
+				Newest: *listNew,
+			}
+			res, err := pulpFilterPackages(version, filter)
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+			for _, p := range res {
+				built := time.Unix(int64(p.Time_build), 0).UTC().Format("2006-01-02 15:04")
+				fmt.Printf("%s\t%s\t%s\t%s\n", packageNevra(p), formatBytes(int64(p.Size_package)), built, p.Location_href)
+			}
 		} else {
-			res, err := pulpRepositoryAll()
+			res, err := pulpRepositoryAll(strings.TrimSpace(*listLbl))
 			if err != nil {
 				fmt.Printf("ERROR %s\n", err.Error())
 				os.Exit(1)
 			}
 			fmt.Println()
-			for i := 0; i < res.Count; i++ {
-				fmt.Printf("%s\n", res.Results[i].Name)
+			for _, repo := range res.Results {
+				fmt.Printf("%s\n", repo.Name)
 			}
 		}
 	case "set":
@@ -388,11 +712,153 @@ func main() {
 			fmt.Printf("ERROR %s\n", err.Error())
 			os.Exit(1)
 		}
+		current, err := pulpDistributionVersion(dist)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		target, err := pulpRepoVersionHref(repo, *setVer)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		changes, err := pulpDiffVersions(current, target)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		err = printDiff(versionLabel(dist, current), fmt.Sprintf("version %d", *setVer), changes, false)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		if !*setYes && !confirm(fmt.Sprintf("Set distribution %s to version %d?", dist, *setVer)) {
+			fmt.Printf("Nothing changed.\n")
+			return
+		}
 		err = pulpSetPubVersion(repo, dist, env, *setVer)
 		if err != nil {
 			fmt.Printf("ERROR %s\n", err.Error())
 			os.Exit(1)
 		}
+	case "diff":
+		diffCmd.Parse(os.Args[2:])
+		if len(diffCmd.Args()) != 2 && len(diffCmd.Args()) != 3 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'diff' subcommand requires either a repository and two versions or two distributions as arguments!\n")
+			usage()
+			os.Exit(1)
+		}
+		var (
+			args             []string
+			fromVer, toVer   int
+			fromName, toName string
+			fromHref, toHref string
+		)
+		for _, arg := range diffCmd.Args() {
+			args = append(args, strings.TrimSpace(arg))
+		}
+		if len(args) == 3 {
+			fromVer, err = strconv.Atoi(args[1])
+			if err == nil {
+				toVer, err = strconv.Atoi(args[2])
+			}
+			if err != nil || fromVer < 0 || toVer < 0 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the versions to compare must be numbers!\n")
+				os.Exit(1)
+			}
+		}
+		err = getAuthorization()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		status, err = pulpStatus()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		fmt.Fprintf(flag.CommandLine.Output(), "Pulp API Status Check: %d\n", status)
+		if status != http.StatusOK {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: try running 'config' again!\n")
+			os.Exit(1)
+		}
+		if len(args) == 3 {
+			fromHref, err = pulpRepoVersionHref(args[0], fromVer)
+			if err == nil {
+				toHref, err = pulpRepoVersionHref(args[0], toVer)
+			}
+			fromName = fmt.Sprintf("%s version %d", args[0], fromVer)
+			toName = fmt.Sprintf("%s version %d", args[0], toVer)
+		} else {
+			fromHref, err = pulpDistributionVersion(args[0])
+			if err == nil {
+				toHref, err = pulpDistributionVersion(args[1])
+			}
+			fromName = versionLabel(args[0], fromHref)
+			toName = versionLabel(args[1], toHref)
+		}
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		changes, err := pulpDiffVersions(fromHref, toHref)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		err = printDiff(fromName, toName, changes, *diffJsn)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+	case "publish":
+		publishCmd.Parse(os.Args[2:])
+		if len(publishCmd.Args()) != 1 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'publish' subcommand requires exactly one repository as argument!\n")
+			usage()
+			os.Exit(1)
+		}
+		repo := strings.TrimSpace(publishCmd.Args()[0])
+		err = getAuthorization()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		settings, err := publishSettings(repo)
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		settings, err = publishOverrides(publishCmd, publishOpt, settings)
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		status, err = pulpStatus()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		fmt.Fprintf(flag.CommandLine.Output(), "Pulp API Status Check: %d\n", status)
+		if status != http.StatusOK {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: try running 'config' again!\n")
+			os.Exit(1)
+		}
+		err = pulpVerifyRepo(repo)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		pub, err := pulpPublishRepo(repo, settings)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		err = pulpDistributePackage(repo, pub)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
 	case "clean":
 		cleanCmd.Parse(os.Args[2:])
 		if len(os.Args) > 2 {
@@ -423,20 +889,31 @@ func main() {
 		for _, p := range progress {
 			fmt.Printf("%s: total %d done %d\n", p.Message, p.Total, p.Done)
 		}
-	case "sync":
-		syncCmd.Parse(os.Args[2:])
-		if len(syncCmd.Args()) != 1 {
-			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'sync' subcommand requires exactly one repository as argument!\n")
+	case "prune-packages":
+		pruneCmd.Parse(os.Args[2:])
+		if len(pruneCmd.Args()) != 1 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'prune-packages' subcommand requires exactly one repository as argument!\n")
 			usage()
 			os.Exit(1)
 		}
-		temp := syncCmd.Args()
-		repo := strings.TrimSpace(temp[0])
+		if *pruneKeep < 0 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -k option requires a positive number!\n")
+			os.Exit(1)
+		}
+		repo := strings.TrimSpace(pruneCmd.Args()[0])
 		err = getAuthorization()
 		if err != nil {
 			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
 			os.Exit(1)
 		}
+		keep := *pruneKeep
+		if keep == 0 {
+			keep = retainPackages(repo)
+		}
+		if keep == 0 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: no retention policy for repository %s, use -k or set retain_packages in the configuration!\n", repo)
+			os.Exit(1)
+		}
 		status, err = pulpStatus()
 		if err != nil {
 			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
@@ -447,20 +924,1015 @@ func main() {
 			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: try running 'config' again!\n")
 			os.Exit(1)
 		}
-		err = pulpSyncRepo(repo)
+		err = pulpVerifyRepo(repo)
 		if err != nil {
 			fmt.Printf("ERROR %s\n", err.Error())
 			os.Exit(1)
 		}
-		pub, err := pulpPublishPackage(repo)
+		packs, err := pulpPackageList(repo, "")
 		if err != nil {
-			testerr := fmt.Sprintf("publication for repository %s already exists", repo)
-			if err.Error() == testerr {
-				fmt.Printf("Repository was already in sync.\n")
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		// The server side retention is only changed once confirmed, along
+		// with removing the packages.
+		retention := ""
+		if *pruneSrv {
+			retention = fmt.Sprintf(" and let Pulp retain %d versions of each package", keep)
+		}
+		prune := prunePackages(packs, keep)
+		if len(prune) == 0 {
+			fmt.Printf("Repository %s holds no more than %d versions of each package.\n", repo, keep)
+			if !*pruneSrv || *pruneDry {
 				return
-			} else {
+			}
+			if !*pruneYes && !confirm(fmt.Sprintf("Let Pulp retain %d versions of each package in repository %s?", keep, repo)) {
+				fmt.Printf("Nothing changed.\n")
+				return
+			}
+			err = pulpSetRetention(repo, keep)
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+			return
+		}
+		var remove []string
+		for _, pack := range prune {
+			fmt.Printf("%s\n", packageNevra(pack))
+			remove = append(remove, pack.Pulp_href)
+		}
+		if *pruneDry {
+			fmt.Printf("%d packages would be removed from repository %s.\n", len(prune), repo)
+			return
+		}
+		if !*pruneYes && !confirm(fmt.Sprintf("Remove these %d packages from repository %s%s?", len(prune), repo, retention)) {
+			fmt.Printf("Nothing removed.\n")
+			return
+		}
+		err = pulpRemoveContentsFromRepo(repo, remove)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		if *pruneSrv {
+			err = pulpSetRetention(repo, keep)
+			if err != nil {
 				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+		}
+		pub, err := pulpPublishPackage(repo)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		err = pulpDistributePackage(repo, pub)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+	case "prune":
+		pruneRepoCmd.Parse(os.Args[2:])
+		if len(*pruneRepoRep) == 0 || len(pruneRepoCmd.Args()) > 0 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'prune' subcommand requires the -r option and no additional arguments!\n")
+			usage()
+			os.Exit(1)
+		}
+		if *pruneRepoKeep < 0 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -keep option requires a number of at least 1!\n")
+			os.Exit(1)
+		}
+		var cutoff time.Time
+		if *pruneRepoAge != "" {
+			age, err := parseAge(*pruneRepoAge)
+			if err != nil || age < 0 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -older-than option requires an age like 30d or 12h!\n")
+				os.Exit(1)
 			}
+			cutoff = time.Now().Add(-age)
+		}
+		repo := strings.TrimSpace(*pruneRepoRep)
+		err = getAuthorization()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		status, err = pulpStatus()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		fmt.Fprintf(flag.CommandLine.Output(), "Pulp API Status Check: %d\n", status)
+		if status != http.StatusOK {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: try running 'config' again!\n")
+			os.Exit(1)
+		}
+		repoInfo, err := pulpRepositoryInfo(repo)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		if repoInfo.Count == 0 {
+			fmt.Printf("ERROR repository %s does not exist!\n", repo)
+			os.Exit(1)
+		}
+		keep := *pruneRepoKeep
+		retain := repoInfo.Results[0].Retain_repo_versions
+		if keep == 0 {
+			keep = retain
+		}
+		if keep == 0 {
+			fmt.Printf("ERROR repository %s has no retain_repo_versions set, use -keep!\n", repo)
+			os.Exit(1)
+		}
+		// Pulp removes the versions beyond retain_repo_versions as soon as a
+		// new version is created, whether a distribution serves them or not.
+		if retain > 0 && retain < keep {
+			fmt.Printf("WARNING repository %s retains only %d repository versions, Pulp will remove the older ones even when served.\n", repo, retain)
+		}
+		versions, err := pulpRepoVersionList(repo)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		publications, err := pulpPublicationList(repo)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		distributions, err := pulpDistributionAll()
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		pubs, remove := pruneRepository(versions, publications, distributions, repoInfo.Results[0], keep, cutoff)
+		if len(pubs) == 0 && len(remove) == 0 {
+			fmt.Printf("Repository %s has nothing to prune.\n", repo)
+			return
+		}
+		for _, pub := range pubs {
+			fmt.Printf("publication %s of version %s\n", pub.Pulp_href, path.Base(pub.Repository_version))
+		}
+		for _, version := range remove {
+			fmt.Printf("version %d created %s\n", version.Number, version.Pulp_created)
+		}
+		if *pruneRepoDry {
+			fmt.Printf("%d publications and %d repository versions would be removed from repository %s.\n", len(pubs), len(remove), repo)
+			return
+		}
+		if !*pruneRepoYes && !confirm(fmt.Sprintf("Remove these %d publications and %d repository versions from repository %s?", len(pubs), len(remove), repo)) {
+			fmt.Printf("Nothing removed.\n")
+			return
+		}
+		for _, pub := range pubs {
+			err = pulpDelPublication(pub)
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+		}
+		for _, version := range remove {
+			err = pulpDelRepoVersion(version.Pulp_href)
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+		}
+		fmt.Printf("Reclaimed %d publications and %d repository versions from repository %s.\n", len(pubs), len(remove), repo)
+		fmt.Printf("Run 'clean' to also remove the packages no longer used.\n")
+	case "copy":
+		copyCmd.Parse(os.Args[2:])
+		if len(*copyFrom) == 0 || len(*copyTo) == 0 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: both the -from and -to options are required for the 'copy' subcommand!\n")
+			os.Exit(1)
+		}
+		if len(copyCmd.Args()) == 0 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'copy' subcommand requires at least one package or advisory as argument!\n")
+			usage()
+			os.Exit(1)
+		}
+		from := strings.TrimSpace(*copyFrom)
+		to := strings.TrimSpace(*copyTo)
+		if from == to {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -from and -to repositories must differ!\n")
+			os.Exit(1)
+		}
+		var args []string
+		for _, arg := range copyCmd.Args() {
+			args = append(args, strings.TrimSpace(arg))
+		}
+		err = getAuthorization()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		status, err = pulpStatus()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		fmt.Fprintf(flag.CommandLine.Output(), "Pulp API Status Check: %d\n", status)
+		if status != http.StatusOK {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: try running 'config' again!\n")
+			os.Exit(1)
+		}
+		version, err := pulpCopy(from, *copyVer, to, args, *copyAdv, *copyDep)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		if version == "" {
+			fmt.Printf("Repository %s is already up to date.\n", to)
+			return
+		}
+		pub, err := pulpPublishPackage(to)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		err = pulpDistributePackage(to, pub)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+	case "search":
+		searchCmd.Parse(os.Args[2:])
+		if len(searchCmd.Args()) != 1 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'search' subcommand requires exactly one name, nevra, sha256, capability or file as argument!\n")
+			usage()
+			os.Exit(1)
+		}
+		if *searchPrv && *searchFil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -provides and -file options can not be combined!\n")
+			os.Exit(1)
+		}
+		term := strings.TrimSpace(searchCmd.Args()[0])
+		mode := ""
+		if *searchPrv {
+			mode = "provides"
+		}
+		if *searchFil {
+			mode = "file"
+		}
+		err = getAuthorization()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		status, err = pulpStatus()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		fmt.Fprintf(flag.CommandLine.Output(), "Pulp API Status Check: %d\n", status)
+		if status != http.StatusOK {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: try running 'config' again!\n")
+			os.Exit(1)
+		}
+		packs, err := pulpSearchPackages(term, mode)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		if len(packs) == 0 {
+			fmt.Printf("No package matches %s.\n", term)
+			return
+		}
+		repos, err := pulpRepositoryAll("")
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		served, err := pulpServedVersions()
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		for _, pack := range packs {
+			locations, err := pulpSearchLocations(pack.Pulp_href, repos.Results, served)
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+			printSearch(pack, locations)
+		}
+	case "sync":
+		syncCmd.Parse(os.Args[2:])
+		if len(syncCmd.Args()) != 1 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'sync' subcommand requires exactly one repository as argument!\n")
+			usage()
+			os.Exit(1)
+		}
+		temp := syncCmd.Args()
+		repo := strings.TrimSpace(temp[0])
+		err = getAuthorization()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		status, err = pulpStatus()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		fmt.Fprintf(flag.CommandLine.Output(), "Pulp API Status Check: %d\n", status)
+		if status != http.StatusOK {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: try running 'config' again!\n")
+			os.Exit(1)
+		}
+		err = pulpSyncRepo(repo, *syncSrc)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		pub, err := pulpPublishPackage(repo)
+		if err != nil {
+			testerr := fmt.Sprintf("publication for repository %s already exists", repo)
+			if err.Error() == testerr {
+				fmt.Printf("Repository was already in sync.\n")
+				return
+			} else {
+				fmt.Printf("ERROR %s\n", err.Error())
+			}
+			os.Exit(1)
+		}
+		err = pulpDistributePackage(repo, pub)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+	case "upload":
+		if len(os.Args) < 3 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'upload' subcommand requires either 'list' or 'abort'!\n")
+			usage()
+			os.Exit(1)
+		}
+		switch os.Args[2] {
+		case "list":
+			uploadListCmd.Parse(os.Args[3:])
+			if len(uploadListCmd.Args()) > 0 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'upload list' subcommand requires no additional arguments!\n")
+				usage()
+				os.Exit(1)
+			}
+			states, err := loadUploadStates()
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+			for _, state := range states {
+				fmt.Printf("%s\t%s\t%d/%d\t%s\n", state.File, state.Upload, uploadedBytes(state), state.Size, time.Unix(0, state.Mtime).Format(time.RFC3339))
+			}
+		case "abort":
+			uploadAbortCmd.Parse(os.Args[3:])
+			if *uploadAll == (len(uploadAbortCmd.Args()) != 0) {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'upload abort' subcommand requires either the -a option or one or more packages as argument!\n")
+				usage()
+				os.Exit(1)
+			}
+			states, err := loadUploadStates()
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+			selected := make(map[string]bool)
+			for _, arg := range uploadAbortCmd.Args() {
+				file, err := filepath.Abs(strings.TrimSpace(arg))
+				if err != nil {
+					fmt.Printf("ERROR %s\n", err.Error())
+					os.Exit(1)
+				}
+				selected[file] = true
+				selected[strings.TrimSpace(arg)] = true
+			}
+			err = getAuthorization()
+			if err != nil {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+				os.Exit(1)
+			}
+			status, err = pulpStatus()
+			if err != nil {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+				os.Exit(1)
+			}
+			fmt.Fprintf(flag.CommandLine.Output(), "Pulp API Status Check: %d\n", status)
+			if status != http.StatusOK {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: try running 'config' again!\n")
+				os.Exit(1)
+			}
+			aborted := 0
+			for _, state := range states {
+				if !*uploadAll && !selected[state.File] && !selected[state.Upload] {
+					continue
+				}
+				err = pulpAbortUpload(state)
+				if err != nil {
+					fmt.Printf("ERROR %s\n", err.Error())
+					os.Exit(1)
+				}
+				fmt.Printf("Upload of %s aborted.\n", state.File)
+				aborted++
+			}
+			if aborted == 0 {
+				fmt.Printf("ERROR no matching pending uploads found!\n")
+				os.Exit(1)
+			}
+		default:
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'upload' subcommand requires either 'list' or 'abort'!\n")
+			usage()
+			os.Exit(1)
+		}
+	case "repo":
+		if len(os.Args) < 3 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'repo' subcommand requires either 'create' or 'delete'!\n")
+			usage()
+			os.Exit(1)
+		}
+		var repo string
+		switch os.Args[2] {
+		case "create":
+			repoCreateCmd.Parse(os.Args[3:])
+			if len(repoCreateCmd.Args()) != 1 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'repo create' subcommand requires exactly one repository as argument!\n")
+				usage()
+				os.Exit(1)
+			}
+			repo = strings.TrimSpace(repoCreateCmd.Args()[0])
+			_, err = deconstructRepository(repo)
+			if err != nil {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+				os.Exit(1)
+			}
+		case "delete":
+			repoDeleteCmd.Parse(os.Args[3:])
+			if len(repoDeleteCmd.Args()) != 1 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'repo delete' subcommand requires exactly one repository as argument!\n")
+				usage()
+				os.Exit(1)
+			}
+			repo = strings.TrimSpace(repoDeleteCmd.Args()[0])
+		default:
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'repo' subcommand requires either 'create' or 'delete'!\n")
+			usage()
+			os.Exit(1)
+		}
+		err = getAuthorization()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		status, err = pulpStatus()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		fmt.Fprintf(flag.CommandLine.Output(), "Pulp API Status Check: %d\n", status)
+		if status != http.StatusOK {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: try running 'config' again!\n")
+			os.Exit(1)
+		}
+		if os.Args[2] == "create" {
+			err = pulpCreateRepo(repo, *repoCreateDsc, strings.TrimSpace(*repoCreateRem))
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+			return
+		}
+		repoInfo, err := pulpRepositoryInfo(repo)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		if repoInfo.Count == 0 {
+			fmt.Printf("ERROR repository %s does not exist!\n", repo)
+			os.Exit(1)
+		}
+		dists, pubs, err := pulpRepoResources(repoInfo.Results[0])
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		var remote *PulpRemote
+		if repoInfo.Results[0].Remote != "" {
+			rem, err := pulpRemoteDetails(repoInfo.Results[0].Remote)
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+			// A remote named otherwise may well be shared with other repositories.
+			if rem.Name == repo {
+				remote = &rem
+			} else {
+				fmt.Printf("Remote %s is not removed, it is not named after the repository.\n", rem.Name)
+			}
+		}
+		for _, dist := range dists {
+			fmt.Printf("distribution %s\t%s\n", dist.Name, dist.Base_path)
+		}
+		fmt.Printf("%d publications\n", len(pubs))
+		if remote != nil {
+			fmt.Printf("remote %s\t%s\n", remote.Name, remote.Url)
+		}
+		if !*repoDeleteYes && !confirm(fmt.Sprintf("Delete repository %s and all of the above?", repo)) {
+			fmt.Printf("Nothing removed.\n")
+			return
+		}
+		err = pulpDelRepo(repoInfo.Results[0], dists, pubs, remote)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("Run 'clean' to also remove the packages no longer used.\n")
+	case "remote":
+		if len(os.Args) < 3 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'remote' subcommand requires either 'create', 'show', 'update', 'delete', 'list', 'attach' or 'detach'!\n")
+			usage()
+			os.Exit(1)
+		}
+		var (
+			name     string
+			repo     string
+			settings RemoteSet
+		)
+		switch os.Args[2] {
+		case "create":
+			remoteCreateCmd.Parse(os.Args[3:])
+			if len(remoteCreateCmd.Args()) != 2 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'remote create' subcommand requires a name and an url as arguments!\n")
+				usage()
+				os.Exit(1)
+			}
+			settings, err = remoteSettings(remoteCreateCmd, remoteCreateOpt)
+			if err != nil {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+				os.Exit(1)
+			}
+			name = strings.TrimSpace(remoteCreateCmd.Args()[0])
+			url := strings.TrimSpace(remoteCreateCmd.Args()[1])
+			settings.Name = &name
+			settings.Url = &url
+			if settings.Policy == nil {
+				settings.Policy = remoteCreateOpt.Policy
+			}
+		case "update":
+			remoteUpdateCmd.Parse(os.Args[3:])
+			if len(remoteUpdateCmd.Args()) != 1 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'remote update' subcommand requires exactly one remote as argument!\n")
+				usage()
+				os.Exit(1)
+			}
+			settings, err = remoteSettings(remoteUpdateCmd, remoteUpdateOpt)
+			if err != nil {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+				os.Exit(1)
+			}
+			if len(*remoteUpdateUrl) != 0 {
+				url := strings.TrimSpace(*remoteUpdateUrl)
+				settings.Url = &url
+			}
+			name = strings.TrimSpace(remoteUpdateCmd.Args()[0])
+		case "show":
+			remoteShowCmd.Parse(os.Args[3:])
+			if len(remoteShowCmd.Args()) != 1 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'remote show' subcommand requires exactly one remote as argument!\n")
+				usage()
+				os.Exit(1)
+			}
+			name = strings.TrimSpace(remoteShowCmd.Args()[0])
+		case "list":
+			remoteListCmd.Parse(os.Args[3:])
+			if len(remoteListCmd.Args()) != 0 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'remote list' subcommand requires no additional arguments!\n")
+				usage()
+				os.Exit(1)
+			}
+		case "delete":
+			remoteDeleteCmd.Parse(os.Args[3:])
+			if len(remoteDeleteCmd.Args()) != 1 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'remote delete' subcommand requires exactly one remote as argument!\n")
+				usage()
+				os.Exit(1)
+			}
+			name = strings.TrimSpace(remoteDeleteCmd.Args()[0])
+		case "attach":
+			remoteAttachCmd.Parse(os.Args[3:])
+			if len(remoteAttachCmd.Args()) != 2 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'remote attach' subcommand requires a remote and a repository as arguments!\n")
+				usage()
+				os.Exit(1)
+			}
+			name = strings.TrimSpace(remoteAttachCmd.Args()[0])
+			repo = strings.TrimSpace(remoteAttachCmd.Args()[1])
+		case "detach":
+			remoteDetachCmd.Parse(os.Args[3:])
+			if len(remoteDetachCmd.Args()) != 1 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'remote detach' subcommand requires exactly one repository as argument!\n")
+				usage()
+				os.Exit(1)
+			}
+			repo = strings.TrimSpace(remoteDetachCmd.Args()[0])
+		default:
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'remote' subcommand requires either 'create', 'show', 'update', 'delete', 'list', 'attach' or 'detach'!\n")
+			usage()
+			os.Exit(1)
+		}
+		err = getAuthorization()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		status, err = pulpStatus()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		fmt.Fprintf(flag.CommandLine.Output(), "Pulp API Status Check: %d\n", status)
+		if status != http.StatusOK {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: try running 'config' again!\n")
+			os.Exit(1)
+		}
+		if repo != "" {
+			err = pulpVerifyRepo(repo)
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+		}
+		switch os.Args[2] {
+		case "create":
+			_, err = pulpCreateRemote(settings)
+		case "update":
+			err = pulpUpdateRemote(name, settings)
+		case "show":
+			remote, err := pulpRemoteByName(name)
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+			users, err := pulpRemoteUsers(remote)
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+			printRemote(remote, users)
+		case "list":
+			remotes, err := pulpRemoteList()
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+			for _, remote := range remotes {
+				fmt.Printf("%s\t%s\t%s\n", remote.Name, remote.Policy, remote.Url)
+			}
+		case "delete":
+			remote, err := pulpRemoteByName(name)
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+			users, err := pulpRemoteUsers(remote)
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+			for _, user := range users {
+				fmt.Printf("Repository %s syncs from remote %s and will be detached.\n", user, name)
+			}
+			if !*remoteDeleteYes && !confirm(fmt.Sprintf("Delete remote %s?", name)) {
+				fmt.Printf("Nothing removed.\n")
+				return
+			}
+			err = pulpDelTask(remote.Pulp_href)
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+			fmt.Printf("Remote %s removed.\n", name)
+		case "attach":
+			err = pulpAttachRemote(repo, name)
+		case "detach":
+			err = pulpAttachRemote(repo, "")
+		}
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+	case "label":
+		if len(os.Args) < 3 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'label' subcommand requires either 'set', 'unset' or 'show'!\n")
+			usage()
+			os.Exit(1)
+		}
+		var (
+			kind   string
+			name   string
+			set    PulpLabels
+			unset  []string
+			labels PulpLabels
+		)
+		switch os.Args[2] {
+		case "set":
+			labelSetCmd.Parse(os.Args[3:])
+			if len(labelSetCmd.Args()) < 2 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'label set' subcommand requires a name and at least one key=value label as arguments!\n")
+				usage()
+				os.Exit(1)
+			}
+			kind = *labelSetTyp
+			name = strings.TrimSpace(labelSetCmd.Args()[0])
+			set, err = parseLabels(labelSetCmd.Args()[1:])
+			if err != nil {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+				os.Exit(1)
+			}
+		case "unset":
+			labelUnsetCmd.Parse(os.Args[3:])
+			if len(labelUnsetCmd.Args()) < 2 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'label unset' subcommand requires a name and at least one key as arguments!\n")
+				usage()
+				os.Exit(1)
+			}
+			kind = *labelUnsetTyp
+			name = strings.TrimSpace(labelUnsetCmd.Args()[0])
+			for _, arg := range labelUnsetCmd.Args()[1:] {
+				unset = append(unset, strings.TrimSpace(arg))
+			}
+		case "show":
+			labelShowCmd.Parse(os.Args[3:])
+			if len(labelShowCmd.Args()) != 1 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'label show' subcommand requires exactly one name as argument!\n")
+				usage()
+				os.Exit(1)
+			}
+			kind = *labelShowTyp
+			name = strings.TrimSpace(labelShowCmd.Args()[0])
+		default:
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'label' subcommand requires either 'set', 'unset' or 'show'!\n")
+			usage()
+			os.Exit(1)
+		}
+		if kind != "repository" && kind != "distribution" && kind != "remote" {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -t option requires either 'repository', 'distribution' or 'remote'!\n")
+			os.Exit(1)
+		}
+		err = getAuthorization()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		status, err = pulpStatus()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		fmt.Fprintf(flag.CommandLine.Output(), "Pulp API Status Check: %d\n", status)
+		if status != http.StatusOK {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: try running 'config' again!\n")
+			os.Exit(1)
+		}
+		if os.Args[2] == "show" {
+			_, labels, err = pulpLabelTarget(kind, name)
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+			printLabels(labels)
+			return
+		}
+		err = pulpUpdateLabels(kind, name, set, unset)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+	case "advisory":
+		if len(os.Args) < 3 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'advisory' subcommand requires either 'add', 'list', 'show' or 'del'!\n")
+			usage()
+			os.Exit(1)
+		}
+		var (
+			repo       string
+			ids        []string
+			advisories []Advisory
+		)
+		switch os.Args[2] {
+		case "add":
+			advisoryAddCmd.Parse(os.Args[3:])
+			if len(*advisoryAddRep) == 0 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -r option is required for the 'advisory add' subcommand!\n")
+				os.Exit(1)
+			}
+			if len(advisoryAddCmd.Args()) == 0 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'advisory add' subcommand requires at least one updateinfo xml or json file as argument!\n")
+				usage()
+				os.Exit(1)
+			}
+			repo = strings.TrimSpace(*advisoryAddRep)
+			for _, arg := range advisoryAddCmd.Args() {
+				adv, err := readAdvisories(strings.TrimSpace(arg))
+				if err != nil {
+					fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+					os.Exit(1)
+				}
+				advisories = append(advisories, adv...)
+			}
+		case "list":
+			advisoryListCmd.Parse(os.Args[3:])
+			if len(advisoryListCmd.Args()) != 1 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'advisory list' subcommand requires exactly one repository as argument!\n")
+				usage()
+				os.Exit(1)
+			}
+			repo = strings.TrimSpace(advisoryListCmd.Args()[0])
+		case "show":
+			advisoryShowCmd.Parse(os.Args[3:])
+			if len(*advisoryShowRep) == 0 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -r option is required for the 'advisory show' subcommand!\n")
+				os.Exit(1)
+			}
+			if len(advisoryShowCmd.Args()) != 1 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'advisory show' subcommand requires exactly one advisory id as argument!\n")
+				usage()
+				os.Exit(1)
+			}
+			repo = strings.TrimSpace(*advisoryShowRep)
+			ids = advisoryShowCmd.Args()
+		case "del":
+			advisoryDelCmd.Parse(os.Args[3:])
+			if len(*advisoryDelRep) == 0 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -r option is required for the 'advisory del' subcommand!\n")
+				os.Exit(1)
+			}
+			if len(advisoryDelCmd.Args()) == 0 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'advisory del' subcommand requires at least one advisory id as argument!\n")
+				usage()
+				os.Exit(1)
+			}
+			repo = strings.TrimSpace(*advisoryDelRep)
+			for _, arg := range advisoryDelCmd.Args() {
+				ids = append(ids, strings.TrimSpace(arg))
+			}
+		default:
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'advisory' subcommand requires either 'add', 'list', 'show' or 'del'!\n")
+			usage()
+			os.Exit(1)
+		}
+		err = getAuthorization()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		status, err = pulpStatus()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		fmt.Fprintf(flag.CommandLine.Output(), "Pulp API Status Check: %d\n", status)
+		if status != http.StatusOK {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: try running 'config' again!\n")
+			os.Exit(1)
+		}
+		err = pulpVerifyRepo(repo)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		switch os.Args[2] {
+		case "list":
+			res, err := pulpAdvisoryList(repo)
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+			for _, adv := range res {
+				fmt.Printf("%s\t%s\t%s\t%s\t%s\n", adv.Id, adv.Type, adv.Severity, adv.Issued_date, adv.Title)
+			}
+			return
+		case "show":
+			adv, err := pulpAdvisoryShow(repo, strings.TrimSpace(ids[0]))
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+			printAdvisory(adv)
+			return
+		case "add":
+			err = pulpAddAdvisories(repo, advisories)
+		case "del":
+			err = pulpDelAdvisories(repo, ids)
+		}
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		pub, err := pulpPublishPackage(repo)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		err = pulpDistributePackage(repo, pub)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+	case "comps":
+		if len(os.Args) < 3 {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'comps' subcommand requires either 'add', 'list' or 'del'!\n")
+			usage()
+			os.Exit(1)
+		}
+		var (
+			repo string
+			ids  []string
+		)
+		switch os.Args[2] {
+		case "add":
+			compsAddCmd.Parse(os.Args[3:])
+			if len(*compsAddRep) == 0 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -r option is required for the 'comps add' subcommand!\n")
+				os.Exit(1)
+			}
+			if len(compsAddCmd.Args()) != 1 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'comps add' subcommand requires exactly one comps.xml file as argument!\n")
+				usage()
+				os.Exit(1)
+			}
+			repo = strings.TrimSpace(*compsAddRep)
+			_, err = os.Stat(strings.TrimSpace(compsAddCmd.Args()[0]))
+			if err != nil {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+				os.Exit(1)
+			}
+		case "list":
+			compsListCmd.Parse(os.Args[3:])
+			if len(compsListCmd.Args()) != 1 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'comps list' subcommand requires exactly one repository as argument!\n")
+				usage()
+				os.Exit(1)
+			}
+			repo = strings.TrimSpace(compsListCmd.Args()[0])
+		case "del":
+			compsDelCmd.Parse(os.Args[3:])
+			if len(*compsDelRep) == 0 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the -r option is required for the 'comps del' subcommand!\n")
+				os.Exit(1)
+			}
+			if len(compsDelCmd.Args()) == 0 {
+				fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'comps del' subcommand requires at least one group id as argument!\n")
+				usage()
+				os.Exit(1)
+			}
+			repo = strings.TrimSpace(*compsDelRep)
+			for _, arg := range compsDelCmd.Args() {
+				ids = append(ids, strings.TrimSpace(arg))
+			}
+		default:
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: the 'comps' subcommand requires either 'add', 'list' or 'del'!\n")
+			usage()
+			os.Exit(1)
+		}
+		err = getAuthorization()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		status, err = pulpStatus()
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		fmt.Fprintf(flag.CommandLine.Output(), "Pulp API Status Check: %d\n", status)
+		if status != http.StatusOK {
+			fmt.Fprintf(flag.CommandLine.Output(), "ERROR: try running 'config' again!\n")
+			os.Exit(1)
+		}
+		err = pulpVerifyRepo(repo)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		switch os.Args[2] {
+		case "list":
+			err = pulpCompsShow(repo, *compsListVer)
+			if err != nil {
+				fmt.Printf("ERROR %s\n", err.Error())
+				os.Exit(1)
+			}
+			return
+		case "add":
+			err = pulpUploadComps(repo, strings.TrimSpace(compsAddCmd.Args()[0]), *compsAddRpl)
+		case "del":
+			err = pulpDelGroups(repo, ids)
+		}
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
+			os.Exit(1)
+		}
+		pub, err := pulpPublishPackage(repo)
+		if err != nil {
+			fmt.Printf("ERROR %s\n", err.Error())
 			os.Exit(1)
 		}
 		err = pulpDistributePackage(repo, pub)
@@ -1,10 +1,11 @@
 /* Pulp CLI
  *
- * - Version 1.1.0 - 2021/08/19
+ * - Version 1.26.0 - 2026/10/19
  */
 package main
 
 import (
+	"bufio"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -12,29 +13,18 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"os/user"
+	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
 )
 
-func calcSHA256(pack string) (string, error) {
-
-	file, err := os.Open(pack)
-	if err != nil {
-		return "", err
-	}
-	defer file.Close()
-	h := sha256.New()
-	if _, err := io.Copy(h, file); err != nil {
-		return "", err
-	}
-	return hex.EncodeToString(h.Sum(nil)), nil
-}
-
 func getFileSize(file string) (int64, error) {
 
 	info, err := os.Stat(file)
@@ -44,45 +34,57 @@ func getFileSize(file string) (int64, error) {
 	return info.Size(), nil
 }
 
-func getAuthorization() error {
+func loadConfiguration() (Configuration, string, error) {
 
 	var config Configuration
 
 	osuser, err := user.Current()
 	if err != nil {
-		return err
+		return config, "", err
 	}
 	adminpath := osuser.HomeDir + "/.pulp/admin.conf"
 	adminfile, err := os.Open(adminpath)
 	if err != nil {
-		return err
-	} else {
-		defer adminfile.Close()
-		decoder := json.NewDecoder(adminfile)
-		config = Configuration{}
-		err = decoder.Decode(&config)
-		if err != nil {
-			return err
-		}
-		apiUser = config.User
-		apiPass = config.Pass
-		apiSrv = config.Url
-		apiEnd = apiSrv + API_ENDPOINT
-		apiClt = &http.Client{Timeout: time.Second * 10}
-		defer apiClt.CloseIdleConnections()
-		return nil
+		return config, adminpath, err
+	}
+	defer adminfile.Close()
+	decoder := json.NewDecoder(adminfile)
+	err = decoder.Decode(&config)
+	if err != nil {
+		return config, adminpath, err
 	}
+	return config, adminpath, nil
 }
 
-func setAuthorization() (string, error) {
-
-	var config Configuration
+func getAuthorization() error {
 
-	osuser, err := user.Current()
+	config, _, err := loadConfiguration()
 	if err != nil {
+		return err
+	}
+	apiCfg = config
+	apiUser = config.User
+	apiPass = config.Pass
+	apiSrv = config.Url
+	apiEnd = apiSrv + API_ENDPOINT
+	apiClt = &http.Client{Timeout: time.Second * 10}
+	defer apiClt.CloseIdleConnections()
+	if config.ChunkSize > 0 {
+		apiChunk = config.ChunkSize
+	}
+	if config.MaxThreads > 0 {
+		apiThreads = config.MaxThreads
+	}
+	return nil
+}
+
+func setAuthorization(chunk int64, threads int) (string, error) {
+
+	// Settings that aren't given on the command line are preserved.
+	config, adminpath, err := loadConfiguration()
+	if err != nil && !os.IsNotExist(err) {
 		return "", err
 	}
-	adminpath := osuser.HomeDir + "/.pulp/admin.conf"
 	_, err = os.Stat(filepath.Dir(adminpath))
 	if os.IsNotExist(err) {
 		err = os.Mkdir(filepath.Dir(adminpath), 0700)
@@ -93,6 +95,12 @@ func setAuthorization() (string, error) {
 	config.User = apiUser
 	config.Pass = apiPass
 	config.Url = apiSrv
+	if chunk > 0 {
+		config.ChunkSize = chunk
+	}
+	if threads > 0 {
+		config.MaxThreads = threads
+	}
 	output, err := json.MarshalIndent(config, " ", " ")
 	if err != nil {
 		return "", err
@@ -106,6 +114,127 @@ func setAuthorization() (string, error) {
 	}
 }
 
+func signaturePolicy(repo string) (string, error) {
+
+	// A repository specific policy overrides the default one.
+	policy := apiCfg.SignaturePolicy
+	settings, ok := apiCfg.Repositories[repo]
+	if ok && settings.SignaturePolicy != "" {
+		policy = settings.SignaturePolicy
+	}
+	switch policy {
+	case "":
+		return SIG_POLICY_OFF, nil
+	case SIG_POLICY_OFF, SIG_POLICY_WARN, SIG_POLICY_REJECT_UNSIGNED, SIG_POLICY_REJECT_UNKNOWN:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown signature policy %s", policy)
+}
+
+func publishSettings(repo string) (PublishSet, error) {
+
+	settings := PublishSet{
+		Repository_version:     "",
+		Metadata_checksum_type: "",
+		Package_checksum_type:  "",
+		Gpgcheck:               nil,
+		Repo_gpgcheck:          nil,
+		Sqlite_metadata:        nil,
+	}
+	repoCfg, ok := apiCfg.Repositories[repo]
+	if ok && repoCfg.Publish != nil {
+		settings = *repoCfg.Publish
+		settings.Repository_version = ""
+	}
+	err := checkPublishSettings(settings)
+	if err != nil {
+		return settings, fmt.Errorf("repository %s: %s", repo, err.Error())
+	}
+	return settings, nil
+}
+
+func retainPackages(repo string) int {
+
+	settings, ok := apiCfg.Repositories[repo]
+	if !ok {
+		return 0
+	}
+	return settings.RetainPackages
+}
+
+func parseAge(age string) (time.Duration, error) {
+
+	var unit time.Duration
+
+	switch {
+	case strings.HasSuffix(age, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(age, "w"):
+		unit = 7 * 24 * time.Hour
+	default:
+		return time.ParseDuration(age)
+	}
+	count, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSuffix(age, "d"), "w"))
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("invalid age %s", age)
+	}
+	return time.Duration(count) * unit, nil
+}
+
+func keyringPaths() ([]string, error) {
+
+	if len(apiCfg.Keyring) > 0 {
+		return apiCfg.Keyring, nil
+	}
+	osuser, err := user.Current()
+	if err != nil {
+		return nil, err
+	}
+	keypath := osuser.HomeDir + "/.pulp/keys"
+	_, err = os.Stat(keypath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return []string{keypath}, nil
+}
+
+func matchPackage(pattern string, pack PulpContent) bool {
+
+	// A pattern may take any of the forms dnf accepts.
+	epoch := pack.Epoch
+	if epoch == "" {
+		epoch = "0"
+	}
+	forms := []string{
+		pack.Name,
+		pack.Name + "." + pack.Arch,
+		pack.Name + "-" + pack.Version,
+		pack.Name + "-" + pack.Version + "-" + pack.Release,
+		pack.Name + "-" + pack.Version + "-" + pack.Release + "." + pack.Arch,
+		pack.Name + "-" + epoch + ":" + pack.Version + "-" + pack.Release + "." + pack.Arch,
+		pack.Name + "-" + pack.Version + "-" + pack.Release + "." + pack.Arch + ".rpm",
+	}
+	for _, form := range forms {
+		matched, err := path.Match(pattern, form)
+		if err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func confirm(question string) bool {
+
+	fmt.Printf("%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Printf("\n")
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
 func version() {
 	program := filepath.Base(os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "%s: version %s\n", program, VERSION)
@@ -115,19 +244,201 @@ func usage() {
 
 	program := filepath.Base(os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "Usage:\n")
-	fmt.Fprintf(flag.CommandLine.Output(), "\t%s config -u user -p password url\n", program)
-	fmt.Fprintf(flag.CommandLine.Output(), "\t%s add    -r repository rpm_package\n", program)
-	fmt.Fprintf(flag.CommandLine.Output(), "\t%s del    -r repository rpm_package\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s config -u user -p password [-c chunksize] [-t threads] url\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s add    -r repository [-a advisories] [-force] rpm_package|glob|directory|url ...\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s add    -r repository -n filename [-s sha256] url|-\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s del    -r repository [-y] rpm_package|nevra|glob ...\n", program)
 	fmt.Fprintf(flag.CommandLine.Output(), "\t%s del    -v version repository\n", program)
-	fmt.Fprintf(flag.CommandLine.Output(), "\t%s list\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s list   [-l selector]\n", program)
 	fmt.Fprintf(flag.CommandLine.Output(), "\t%s list   -v repository\n", program)
 	fmt.Fprintf(flag.CommandLine.Output(), "\t%s list   -d repository\n", program)
-	fmt.Fprintf(flag.CommandLine.Output(), "\t%s set    -v version distribution\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s list   -p [-V version|-e environment] [-t bin|src] [-n glob] [-a arch] [-N] repository\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s set    -v version [-y] distribution\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s diff   [-json] repository version version\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s diff   [-json] distribution distribution\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s publish [-checksum type] [-metadata-checksum type] [-package-checksum type] [-gpgcheck 0|1] [-repo-gpgcheck 0|1] [-sqlite] repository\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s copy -from repository [-v version] -to repository [-deps] rpm_package|nevra|glob ...\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s copy -from repository [-v version] -to repository [-deps] -a advisory ...\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s search [-provides|-file] name|nevra|glob|sha256|capability|file\n", program)
 	fmt.Fprintf(flag.CommandLine.Output(), "\t%s clean\n", program)
-	fmt.Fprintf(flag.CommandLine.Output(), "\t%s sync repository\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s repo create [-d description] [-remote url] repository\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s repo delete [-y] repository\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s remote create [remote options] name url\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s remote update [-url url] [remote options] name\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s remote show name\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s remote list\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s remote delete [-y] name\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s remote attach name repository\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s remote detach repository\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s label set [-t kind] name key=value ...\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s label unset [-t kind] name key ...\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s label show [-t kind] name\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s prune-packages [-k keep] [-n] [-y] [-server] repository\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s prune -r repository [-keep number] [-older-than age] [-n] [-y]\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s sync   [-with-srpm] repository\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s upload list\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s upload abort -a | rpm_package ...\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s advisory add  -r repository updateinfo ...\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s advisory list repository\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s advisory show -r repository id\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s advisory del  -r repository id ...\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s comps add  -r repository [-replace] comps.xml\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s comps list [-v version] repository\n", program)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%s comps del  -r repository group ...\n", program)
 	fmt.Fprintf(flag.CommandLine.Output(), "\t%s version\n", program)
 }
 
+func resolvePackages(args []string, name, sum string) ([]PackageSource, error) {
+
+	var packs []PackageSource
+
+	if (len(name) != 0 || len(sum) != 0) && len(args) != 1 {
+		return nil, fmt.Errorf("a filename or checksum can only be given for a single package")
+	}
+	if len(name) != 0 && filepath.Ext(name) != ".rpm" {
+		return nil, fmt.Errorf("%s is not an rpm package", name)
+	}
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		var matches []string
+		arg = strings.TrimSpace(arg)
+		if arg == "-" || strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "https://") {
+			src := PackageSource{
+				Origin: arg,
+				Path:   "",
+				Name:   name,
+				Sha256: strings.ToLower(sum),
+				Temp:   false,
+			}
+			if arg == "-" {
+				if len(name) == 0 {
+					return nil, fmt.Errorf("reading a package from stdin requires a filename")
+				}
+				if seen[arg] {
+					return nil, fmt.Errorf("stdin can only be used once")
+				}
+			} else if len(name) == 0 {
+				link, err := url.Parse(arg)
+				if err != nil {
+					return nil, err
+				}
+				src.Name = path.Base(link.Path)
+				if filepath.Ext(src.Name) != ".rpm" {
+					return nil, fmt.Errorf("%s does not point to an rpm package, use a filename", arg)
+				}
+			}
+			if !seen[arg] {
+				seen[arg] = true
+				packs = append(packs, src)
+			}
+			continue
+		}
+		if len(sum) != 0 {
+			return nil, fmt.Errorf("a checksum can only be given for packages read from an url or stdin")
+		}
+		info, err := os.Stat(arg)
+		if err == nil && info.IsDir() {
+			entries, err := os.ReadDir(arg)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range entries {
+				if !entry.IsDir() && filepath.Ext(entry.Name()) == ".rpm" {
+					matches = append(matches, filepath.Join(arg, entry.Name()))
+				}
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("directory %s does not contain any rpm packages", arg)
+			}
+		} else if strings.ContainsAny(arg, "*?[") {
+			matches, err = filepath.Glob(arg)
+			if err != nil {
+				return nil, err
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("%s does not match any rpm packages", arg)
+			}
+		} else {
+			matches = append(matches, arg)
+		}
+		for _, match := range matches {
+			if filepath.Ext(match) != ".rpm" {
+				return nil, fmt.Errorf("%s is not an rpm package", match)
+			}
+			if !seen[match] {
+				seen[match] = true
+				src := PackageSource{
+					Origin: match,
+					Path:   match,
+					Name:   filepath.Base(match),
+					Sha256: "",
+					Temp:   false,
+				}
+				if len(name) != 0 {
+					src.Name = name
+				}
+				packs = append(packs, src)
+			}
+		}
+	}
+	return packs, nil
+}
+
+func fetchPackage(src *PackageSource) error {
+
+	var (
+		input io.Reader
+		total int64
+	)
+
+	if src.Origin == "-" {
+		input = os.Stdin
+	} else {
+		client := &http.Client{Timeout: time.Second * CLIENT_TIMEOUT}
+		defer client.CloseIdleConnections()
+		response, err := client.Get(src.Origin)
+		if err != nil {
+			return err
+		}
+		defer response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			return fmt.Errorf("HTTP response: %d, expected: %d", response.StatusCode, http.StatusOK)
+		}
+		input = response.Body
+		if response.ContentLength > 0 {
+			total = response.ContentLength
+		}
+	}
+	file, err := os.CreateTemp("", "pulp-admin-*.rpm")
+	if err != nil {
+		return err
+	}
+	src.Path = file.Name()
+	src.Temp = true
+	// The checksum is calculated while the package is being streamed
+	// to disk, so there's no need to read it back again.
+	h := sha256.New()
+	progress := newProgress("Downloading "+src.Name, total, 0)
+	// Finish the progress line on every return, so no message ends up
+	// behind a half drawn line.
+	defer progressDone(progress)
+	_, err = io.Copy(io.MultiWriter(file, h), newProgressReader(progress, input))
+	if err != nil {
+		file.Close()
+		return err
+	}
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	if src.Sha256 != "" && src.Sha256 != sum {
+		return fmt.Errorf("checksum mismatch for %s: got %s, expected %s", src.Origin, sum, src.Sha256)
+	}
+	src.Sha256 = sum
+	return nil
+}
+
 func deconstructRepository(repo string) (RepoDetails, error) {
 
 	var (
@@ -143,8 +454,11 @@ func deconstructRepository(repo string) (RepoDetails, error) {
 	regular := regexp.MustCompile(`-`)
 	dashsplit := regular.Split(repo, -1)
 	length := len(dashsplit)
+	if length < 3 {
+		return oi, fmt.Errorf("%s is not named like name-osrelease-arch", repo)
+	}
 	oi.Architecture = dashsplit[length-1]
-	if oi.Architecture != "x86_64" {
+	if oi.Architecture != "x86_64" && oi.Architecture != "src" {
 		return oi, fmt.Errorf("%s is an unsupported hardware platform abbreviation", oi.Architecture)
 	}
 	for _, char := range dashsplit[length-2] {
@@ -172,33 +486,61 @@ func deconstructRepository(repo string) (RepoDetails, error) {
 	return oi, nil
 }
 
+func localPackageDetails(pack string) (PackageDetails, error) {
+
+	// If the package is available locally, its header tells us exactly
+	// what it is. Otherwise we have to guess from the filename.
+	_, err := os.Stat(pack)
+	if err != nil {
+		return deconstructPackage(filepath.Base(pack)), nil
+	}
+	pkg, err := readRpmPackage(pack, false)
+	if err != nil {
+		return PackageDetails{}, err
+	}
+	details := PackageDetails{
+		Name:    pkg.Name,
+		Epoch:   pkg.Epoch,
+		Version: pkg.Version,
+		Release: pkg.Release,
+		Arch:    pkg.Arch,
+	}
+	return details, nil
+}
+
 func deconstructPackage(pack string) PackageDetails {
 
 	var packinfo PackageDetails
 
 	packinfo.Release = "1" // The release is often not set and is 1 by default.
-	regular := regexp.MustCompile(`\.`)
-	dotsplit := regular.Split(pack, -1)
-	length := len(dotsplit)
-	packinfo.Arch = dotsplit[length-2]
-	remainder := strings.Join(dotsplit[:length-2], ".")
-	regular = regexp.MustCompile(`-`)
-	dashsplit := regular.Split(remainder, -1)
-	for i, v := range dashsplit {
-		r := []rune(v)
-		for j := 0; j < len(r); j++ {
-			if unicode.IsDigit(r[j]) {
-				if packinfo.Version == "" {
-					packinfo.Name = strings.Join(dashsplit[:i], "-")
-					packinfo.Version = v
-				} else {
-					packinfo.Release = v
-					goto END
-				}
-				break
-			}
-		}
+	// A package is named name-[epoch:]version-release.arch.rpm, where the
+	// name itself may contain dashes and digits, so we work from the end.
+	pack = strings.TrimSuffix(pack, ".rpm")
+	dot := strings.LastIndex(pack, ".")
+	if dot >= 0 {
+		packinfo.Arch = pack[dot+1:]
+		pack = pack[:dot]
+	}
+	dashsplit := strings.Split(pack, "-")
+	length := len(dashsplit)
+	startsWithDigit := func(s string) bool {
+		return s != "" && unicode.IsDigit([]rune(s)[0])
+	}
+	switch {
+	case length >= 3 && startsWithDigit(dashsplit[length-2]):
+		packinfo.Name = strings.Join(dashsplit[:length-2], "-")
+		packinfo.Version = dashsplit[length-2]
+		packinfo.Release = dashsplit[length-1]
+	case length >= 2:
+		packinfo.Name = strings.Join(dashsplit[:length-1], "-")
+		packinfo.Version = dashsplit[length-1]
+	default:
+		packinfo.Name = pack
+	}
+	colon := strings.Index(packinfo.Version, ":")
+	if colon >= 0 {
+		packinfo.Epoch = packinfo.Version[:colon]
+		packinfo.Version = packinfo.Version[colon+1:]
 	}
-END:
 	return packinfo
 }
@@ -0,0 +1,740 @@
+/* Pulp CLI
+ *
+ * - Version 1.9.0 - 2026/10/19
+ */
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto"
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "crypto/md5"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+const (
+	PGP_TAG_SIGNATURE  int = 2
+	PGP_TAG_PUBLIC_KEY int = 6
+	PGP_TAG_USER_ID    int = 13
+	PGP_TAG_PUBLIC_SUB int = 14
+
+	PGP_ALGO_RSA         byte = 1
+	PGP_ALGO_RSA_SIGN    byte = 3
+	PGP_ALGO_DSA         byte = 17
+	PGP_ALGO_ECDSA       byte = 19
+	PGP_ALGO_EDDSA       byte = 22
+	PGP_SUB_CREATED      byte = 2
+	PGP_SUB_EXPIRES      byte = 3
+	PGP_SUB_KEY_EXPIRES  byte = 9
+	PGP_SUB_ISSUER       byte = 16
+	PGP_SUB_KEY_FLAGS    byte = 27
+	PGP_SUB_EMBEDDED     byte = 32
+	PGP_SUB_ISSUER_FPR   byte = 33
+	PGP_KEY_FLAG_SIGN    byte = 0x02
+	PGP_ARMOR_BEGIN_KEYS      = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+	PGP_ARMOR_END_KEYS        = "-----END PGP PUBLIC KEY BLOCK-----"
+
+	PGP_SIG_CERT_GENERIC      byte = 0x10
+	PGP_SIG_CERT_POSITIVE     byte = 0x13
+	PGP_SIG_SUBKEY_BINDING    byte = 0x18
+	PGP_SIG_PRIMARY_BINDING   byte = 0x19
+	PGP_SIG_KEY_REVOCATION    byte = 0x20
+	PGP_SIG_SUBKEY_REVOCATION byte = 0x28
+)
+
+var (
+	pgpCurveP256    = []byte{0x2a, 0x86, 0x48, 0xce, 0x3d, 0x03, 0x01, 0x07}
+	pgpCurveP384    = []byte{0x2b, 0x81, 0x04, 0x00, 0x22}
+	pgpCurveP521    = []byte{0x2b, 0x81, 0x04, 0x00, 0x23}
+	pgpCurveEd25519 = []byte{0x2b, 0x06, 0x01, 0x04, 0x01, 0xda, 0x47, 0x0f, 0x01}
+)
+
+func pgpHash(algo byte) (crypto.Hash, error) {
+
+	switch algo {
+	case 1:
+		return crypto.MD5, nil
+	case 2:
+		return crypto.SHA1, nil
+	case 8:
+		return crypto.SHA256, nil
+	case 9:
+		return crypto.SHA384, nil
+	case 10:
+		return crypto.SHA512, nil
+	case 11:
+		return crypto.SHA224, nil
+	}
+	return 0, fmt.Errorf("unsupported signature hash algorithm %d", algo)
+}
+
+func pgpPacketLength(data []byte) (int, int, bool, error) {
+
+	// Returns the length, the number of octets used to encode it and
+	// whether this is a partial body length.
+	if len(data) < 1 {
+		return 0, 0, false, fmt.Errorf("truncated packet length")
+	}
+	switch {
+	case data[0] < 192:
+		return int(data[0]), 1, false, nil
+	case data[0] < 224:
+		if len(data) < 2 {
+			return 0, 0, false, fmt.Errorf("truncated packet length")
+		}
+		return (int(data[0])-192)<<8 + int(data[1]) + 192, 2, false, nil
+	case data[0] == 255:
+		if len(data) < 5 {
+			return 0, 0, false, fmt.Errorf("truncated packet length")
+		}
+		return int(binary.BigEndian.Uint32(data[1:5])), 5, false, nil
+	default:
+		return 1 << (data[0] & 0x1f), 1, true, nil
+	}
+}
+
+func pgpSubpacketLength(data []byte) (int, int, error) {
+
+	if len(data) < 1 {
+		return 0, 0, fmt.Errorf("truncated subpacket length")
+	}
+	switch {
+	case data[0] < 192:
+		return int(data[0]), 1, nil
+	case data[0] < 255:
+		if len(data) < 2 {
+			return 0, 0, fmt.Errorf("truncated subpacket length")
+		}
+		return (int(data[0])-192)<<8 + int(data[1]) + 192, 2, nil
+	default:
+		if len(data) < 5 {
+			return 0, 0, fmt.Errorf("truncated subpacket length")
+		}
+		return int(binary.BigEndian.Uint32(data[1:5])), 5, nil
+	}
+}
+
+func readPgpPackets(data []byte) ([]PgpPacket, error) {
+
+	var packets []PgpPacket
+
+	for len(data) > 0 {
+		var (
+			packet PgpPacket
+			length int
+		)
+
+		if data[0]&0x80 == 0 {
+			return nil, fmt.Errorf("invalid packet header")
+		}
+		if data[0]&0x40 != 0 {
+			// New packet format, possibly split in partial bodies.
+			packet.Tag = int(data[0] & 0x3f)
+			data = data[1:]
+			for {
+				l, n, partial, err := pgpPacketLength(data)
+				if err != nil {
+					return nil, err
+				}
+				if len(data) < n+l {
+					return nil, fmt.Errorf("truncated packet")
+				}
+				packet.Body = append(packet.Body, data[n:n+l]...)
+				data = data[n+l:]
+				if !partial {
+					break
+				}
+			}
+		} else {
+			packet.Tag = int(data[0]>>2) & 0x0f
+			switch data[0] & 0x03 {
+			case 0:
+				if len(data) < 2 {
+					return nil, fmt.Errorf("truncated packet")
+				}
+				length = int(data[1])
+				data = data[2:]
+			case 1:
+				if len(data) < 3 {
+					return nil, fmt.Errorf("truncated packet")
+				}
+				length = int(binary.BigEndian.Uint16(data[1:3]))
+				data = data[3:]
+			case 2:
+				if len(data) < 5 {
+					return nil, fmt.Errorf("truncated packet")
+				}
+				length = int(binary.BigEndian.Uint32(data[1:5]))
+				data = data[5:]
+			default:
+				length = len(data) - 1
+				data = data[1:]
+			}
+			if len(data) < length {
+				return nil, fmt.Errorf("truncated packet")
+			}
+			packet.Body = data[:length]
+			data = data[length:]
+		}
+		packets = append(packets, packet)
+	}
+	return packets, nil
+}
+
+func readPgpMPI(data []byte) ([]byte, []byte, error) {
+
+	if len(data) < 2 {
+		return nil, nil, fmt.Errorf("truncated MPI")
+	}
+	bits := int(binary.BigEndian.Uint16(data[0:2]))
+	length := (bits + 7) / 8
+	if len(data) < 2+length {
+		return nil, nil, fmt.Errorf("truncated MPI")
+	}
+	return data[2 : 2+length], data[2+length:], nil
+}
+
+func readPgpMPIs(data []byte, count int) ([][]byte, error) {
+
+	var (
+		result [][]byte
+		mpi    []byte
+		err    error
+	)
+
+	for i := 0; i < count; i++ {
+		mpi, data, err = readPgpMPI(data)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, mpi)
+	}
+	return result, nil
+}
+
+func readPgpCurve(data []byte) ([]byte, []byte, error) {
+
+	if len(data) < 1 || len(data) < 1+int(data[0]) {
+		return nil, nil, fmt.Errorf("truncated curve OID")
+	}
+	return data[1 : 1+int(data[0])], data[1+int(data[0]):], nil
+}
+
+func readPgpPublicKey(body []byte) (PgpKey, error) {
+
+	var key PgpKey
+
+	if len(body) < 6 || body[0] != 4 {
+		return key, fmt.Errorf("unsupported public key version")
+	}
+	// The fingerprint of a version 4 key is the SHA1 of the packet with
+	// a fixed old style header.
+	h := sha1.New()
+	h.Write([]byte{0x99, byte(len(body) >> 8), byte(len(body))})
+	h.Write(body)
+	key.Fingerprint = h.Sum(nil)
+	key.KeyId = binary.BigEndian.Uint64(key.Fingerprint[12:20])
+	key.Created = time.Unix(int64(binary.BigEndian.Uint32(body[1:5])), 0)
+	key.Algo = body[5]
+	material := body[6:]
+	switch key.Algo {
+	case PGP_ALGO_RSA, PGP_ALGO_RSA_SIGN:
+		mpis, err := readPgpMPIs(material, 2)
+		if err != nil {
+			return key, err
+		}
+		key.Public = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(mpis[0]),
+			E: int(new(big.Int).SetBytes(mpis[1]).Int64()),
+		}
+	case PGP_ALGO_DSA:
+		mpis, err := readPgpMPIs(material, 4)
+		if err != nil {
+			return key, err
+		}
+		key.Public = &dsa.PublicKey{
+			Parameters: dsa.Parameters{
+				P: new(big.Int).SetBytes(mpis[0]),
+				Q: new(big.Int).SetBytes(mpis[1]),
+				G: new(big.Int).SetBytes(mpis[2]),
+			},
+			Y: new(big.Int).SetBytes(mpis[3]),
+		}
+	case PGP_ALGO_ECDSA:
+		oid, rest, err := readPgpCurve(material)
+		if err != nil {
+			return key, err
+		}
+		var curve elliptic.Curve
+		switch {
+		case bytes.Equal(oid, pgpCurveP256):
+			curve = elliptic.P256()
+		case bytes.Equal(oid, pgpCurveP384):
+			curve = elliptic.P384()
+		case bytes.Equal(oid, pgpCurveP521):
+			curve = elliptic.P521()
+		default:
+			return key, fmt.Errorf("unsupported ECDSA curve")
+		}
+		point, _, err := readPgpMPI(rest)
+		if err != nil {
+			return key, err
+		}
+		x, y := elliptic.Unmarshal(curve, point)
+		if x == nil {
+			return key, fmt.Errorf("invalid ECDSA public key")
+		}
+		key.Public = &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+	case PGP_ALGO_EDDSA:
+		oid, rest, err := readPgpCurve(material)
+		if err != nil {
+			return key, err
+		}
+		if !bytes.Equal(oid, pgpCurveEd25519) {
+			return key, fmt.Errorf("unsupported EdDSA curve")
+		}
+		point, _, err := readPgpMPI(rest)
+		if err != nil {
+			return key, err
+		}
+		// The point is prefixed with 0x40 to mark its native encoding.
+		if len(point) != ed25519.PublicKeySize+1 || point[0] != 0x40 {
+			return key, fmt.Errorf("invalid EdDSA public key")
+		}
+		key.Public = ed25519.PublicKey(point[1:])
+	default:
+		return key, fmt.Errorf("unsupported public key algorithm %d", key.Algo)
+	}
+	return key, nil
+}
+
+func readPgpSignature(body []byte) (PgpSignature, error) {
+
+	var sig PgpSignature
+
+	if len(body) < 1 {
+		return sig, fmt.Errorf("empty signature packet")
+	}
+	sig.Version = body[0]
+	switch sig.Version {
+	case 3:
+		if len(body) < 19 || body[1] != 5 {
+			return sig, fmt.Errorf("invalid version 3 signature")
+		}
+		sig.Hashed = body[2:7]
+		sig.SigType = body[2]
+		sig.Created = time.Unix(int64(binary.BigEndian.Uint32(body[3:7])), 0)
+		sig.KeyId = binary.BigEndian.Uint64(body[7:15])
+		sig.PubAlgo = body[15]
+		sig.HashAlgo = body[16]
+		sig.Left16 = body[17:19]
+		sig.Material = body[19:]
+	case 4:
+		if len(body) < 6 {
+			return sig, fmt.Errorf("truncated signature")
+		}
+		sig.SigType = body[1]
+		sig.PubAlgo = body[2]
+		sig.HashAlgo = body[3]
+		hashedLength := int(binary.BigEndian.Uint16(body[4:6]))
+		if len(body) < 6+hashedLength+2 {
+			return sig, fmt.Errorf("truncated signature")
+		}
+		sig.Hashed = body[:6+hashedLength]
+		unhashedLength := int(binary.BigEndian.Uint16(body[6+hashedLength : 8+hashedLength]))
+		rest := body[8+hashedLength:]
+		if len(rest) < unhashedLength+2 {
+			return sig, fmt.Errorf("truncated signature")
+		}
+		// The issuer and embedded signature may be found in either the
+		// hashed or unhashed area, they are verified anyway. Times and key
+		// flags are only to be trusted when hashed.
+		for i, area := range [][]byte{body[6 : 6+hashedLength], rest[:unhashedLength]} {
+			hashed := i == 0
+			for len(area) > 0 {
+				length, n, err := pgpSubpacketLength(area)
+				if err != nil || length < 1 || len(area) < n+length {
+					return sig, fmt.Errorf("invalid signature subpacket")
+				}
+				subpacket := area[n : n+length]
+				switch subpacket[0] & 0x7f {
+				case PGP_SUB_ISSUER:
+					if len(subpacket) == 9 {
+						sig.KeyId = binary.BigEndian.Uint64(subpacket[1:9])
+					}
+				case PGP_SUB_ISSUER_FPR:
+					if len(subpacket) == 22 && subpacket[1] == 4 {
+						sig.KeyId = binary.BigEndian.Uint64(subpacket[14:22])
+					}
+				case PGP_SUB_EMBEDDED:
+					sig.Embedded = subpacket[1:]
+				case PGP_SUB_CREATED:
+					if hashed && len(subpacket) == 5 {
+						sig.Created = time.Unix(int64(binary.BigEndian.Uint32(subpacket[1:5])), 0)
+					}
+				case PGP_SUB_EXPIRES:
+					if hashed && len(subpacket) == 5 {
+						sig.Expires = time.Duration(binary.BigEndian.Uint32(subpacket[1:5])) * time.Second
+					}
+				case PGP_SUB_KEY_EXPIRES:
+					if hashed && len(subpacket) == 5 {
+						sig.KeyExpires = time.Duration(binary.BigEndian.Uint32(subpacket[1:5])) * time.Second
+					}
+				case PGP_SUB_KEY_FLAGS:
+					if hashed {
+						sig.KeyFlags = subpacket[1:]
+					}
+				}
+				area = area[n+length:]
+			}
+		}
+		sig.Left16 = rest[unhashedLength : unhashedLength+2]
+		sig.Material = rest[unhashedLength+2:]
+	default:
+		return sig, fmt.Errorf("unsupported signature version %d", sig.Version)
+	}
+	return sig, nil
+}
+
+func pgpSignatureHash(sig PgpSignature) (hash.Hash, error) {
+
+	algo, err := pgpHash(sig.HashAlgo)
+	if err != nil {
+		return nil, err
+	}
+	// MD5 and SHA1 signatures can be forged, no matter how good the key is.
+	if algo == crypto.MD5 || algo == crypto.SHA1 {
+		return nil, fmt.Errorf("weak signature hash algorithm %s", algo.String())
+	}
+	if !algo.Available() {
+		return nil, fmt.Errorf("unavailable signature hash algorithm %d", sig.HashAlgo)
+	}
+	return algo.New(), nil
+}
+
+func verifyPgpSignature(sig PgpSignature, key PgpKey, h hash.Hash) error {
+
+	// Whatever was signed, is followed by the hashed part of the signature.
+	h.Write(sig.Hashed)
+	if sig.Version == 4 {
+		trailer := []byte{4, 0xff, 0, 0, 0, 0}
+		binary.BigEndian.PutUint32(trailer[2:], uint32(len(sig.Hashed)))
+		h.Write(trailer)
+	}
+	digest := h.Sum(nil)
+	if digest[0] != sig.Left16[0] || digest[1] != sig.Left16[1] {
+		return fmt.Errorf("signature digest mismatch")
+	}
+	algo, _ := pgpHash(sig.HashAlgo)
+	switch public := key.Public.(type) {
+	case *rsa.PublicKey:
+		mpis, err := readPgpMPIs(sig.Material, 1)
+		if err != nil {
+			return err
+		}
+		// The MPI drops leading zeros, but PKCS1 needs the full length.
+		signature := make([]byte, (public.N.BitLen()+7)/8)
+		if len(mpis[0]) > len(signature) {
+			return fmt.Errorf("invalid RSA signature")
+		}
+		copy(signature[len(signature)-len(mpis[0]):], mpis[0])
+		return rsa.VerifyPKCS1v15(public, algo, digest, signature)
+	case *dsa.PublicKey:
+		mpis, err := readPgpMPIs(sig.Material, 2)
+		if err != nil {
+			return err
+		}
+		size := (public.Q.BitLen() + 7) / 8
+		if len(digest) > size {
+			digest = digest[:size]
+		}
+		if !dsa.Verify(public, digest, new(big.Int).SetBytes(mpis[0]), new(big.Int).SetBytes(mpis[1])) {
+			return fmt.Errorf("invalid DSA signature")
+		}
+	case *ecdsa.PublicKey:
+		mpis, err := readPgpMPIs(sig.Material, 2)
+		if err != nil {
+			return err
+		}
+		if !ecdsa.Verify(public, digest, new(big.Int).SetBytes(mpis[0]), new(big.Int).SetBytes(mpis[1])) {
+			return fmt.Errorf("invalid ECDSA signature")
+		}
+	case ed25519.PublicKey:
+		mpis, err := readPgpMPIs(sig.Material, 2)
+		if err != nil {
+			return err
+		}
+		if len(mpis[0]) > 32 || len(mpis[1]) > 32 {
+			return fmt.Errorf("invalid EdDSA signature")
+		}
+		signature := make([]byte, 64)
+		copy(signature[32-len(mpis[0]):32], mpis[0])
+		copy(signature[64-len(mpis[1]):], mpis[1])
+		if !ed25519.Verify(public, digest, signature) {
+			return fmt.Errorf("invalid EdDSA signature")
+		}
+	default:
+		return fmt.Errorf("unsupported public key")
+	}
+	return nil
+}
+
+func verifyPgpKeySignature(sig PgpSignature, signer PgpKey, primary, subkey, userId []byte, now time.Time) bool {
+
+	if sig.KeyId != 0 && sig.KeyId != signer.KeyId {
+		return false
+	}
+	if sig.Expires > 0 && !now.Before(sig.Created.Add(sig.Expires)) {
+		return false
+	}
+	h, err := pgpSignatureHash(sig)
+	if err != nil {
+		return false
+	}
+	// A key is hashed with the header of a version 4 key packet, a user id
+	// with one of its own.
+	for _, key := range [][]byte{primary, subkey} {
+		if key != nil {
+			h.Write([]byte{0x99, byte(len(key) >> 8), byte(len(key))})
+			h.Write(key)
+		}
+	}
+	if userId != nil {
+		if sig.Version == 4 {
+			header := []byte{0xb4, 0, 0, 0, 0}
+			binary.BigEndian.PutUint32(header[1:], uint32(len(userId)))
+			h.Write(header)
+		}
+		h.Write(userId)
+	}
+	return verifyPgpSignature(sig, signer, h) == nil
+}
+
+func pgpKeyExpired(key PgpKey, self PgpSignature, now time.Time) bool {
+
+	return self.KeyExpires > 0 && !now.Before(key.Created.Add(self.KeyExpires))
+}
+
+func pgpKeyMaySign(self PgpSignature) bool {
+
+	return self.KeyFlags == nil || len(self.KeyFlags) > 0 && self.KeyFlags[0]&PGP_KEY_FLAG_SIGN != 0
+}
+
+func readPgpCertificate(packets []PgpPacket, now time.Time) []PgpKey {
+
+	var (
+		keys      []PgpKey
+		certified PgpSignature // The newest self certification, if any.
+		userId    []byte
+		subject   int
+		bindings  = map[int]PgpSignature{}
+		revoked   = map[int]bool{}
+	)
+
+	primary, err := readPgpPublicKey(packets[0].Body)
+	if err != nil {
+		// Keys we can't use are simply skipped.
+		return nil
+	}
+	// Signatures follow the key or user id they are about. A subkey is known
+	// by the user id of its primary key.
+	for i, packet := range packets {
+		switch packet.Tag {
+		case PGP_TAG_USER_ID:
+			userId = packet.Body
+			if primary.UserId == "" {
+				primary.UserId = string(packet.Body)
+			}
+		case PGP_TAG_PUBLIC_SUB:
+			subject = i
+			userId = nil
+		case PGP_TAG_SIGNATURE:
+			sig, err := readPgpSignature(packet.Body)
+			if err != nil {
+				continue
+			}
+			var subkey []byte
+			if subject > 0 {
+				subkey = packets[subject].Body
+			}
+			switch {
+			case subject == 0 && sig.SigType == PGP_SIG_KEY_REVOCATION, subject > 0 && sig.SigType == PGP_SIG_SUBKEY_REVOCATION:
+				if verifyPgpKeySignature(sig, primary, packets[0].Body, subkey, nil, now) {
+					revoked[subject] = true
+				}
+			case subject == 0 && userId != nil && sig.SigType >= PGP_SIG_CERT_GENERIC && sig.SigType <= PGP_SIG_CERT_POSITIVE:
+				if sig.Created.After(certified.Created) && verifyPgpKeySignature(sig, primary, packets[0].Body, nil, userId, now) {
+					certified = sig
+				}
+			case subject > 0 && sig.SigType == PGP_SIG_SUBKEY_BINDING:
+				if sig.Created.After(bindings[subject].Created) && verifyPgpKeySignature(sig, primary, packets[0].Body, subkey, nil, now) {
+					bindings[subject] = sig
+				}
+			}
+		}
+	}
+	// Without a self certification we can verify, e.g. one made with SHA1,
+	// the primary key is taken as is.
+	if revoked[0] || pgpKeyExpired(primary, certified, now) {
+		return nil
+	}
+	if pgpKeyMaySign(certified) {
+		keys = append(keys, primary)
+	}
+	for i := range packets {
+		binding, bound := bindings[i]
+		if !bound || revoked[i] {
+			continue
+		}
+		subkey, err := readPgpPublicKey(packets[i].Body)
+		if err != nil || pgpKeyExpired(subkey, binding, now) || !pgpKeyMaySign(binding) {
+			continue
+		}
+		// A signing subkey must sign the primary key in return, or anyone
+		// could claim it as theirs.
+		back, err := readPgpSignature(binding.Embedded)
+		if err != nil || back.SigType != PGP_SIG_PRIMARY_BINDING || !verifyPgpKeySignature(back, subkey, packets[0].Body, packets[i].Body, nil, now) {
+			continue
+		}
+		subkey.UserId = primary.UserId
+		keys = append(keys, subkey)
+	}
+	return keys
+}
+
+func decodePgpArmor(content []byte) ([][]byte, error) {
+
+	var (
+		blocks [][]byte
+		body   strings.Builder
+		inside bool
+		header bool
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == PGP_ARMOR_BEGIN_KEYS:
+			inside = true
+			header = true
+			body.Reset()
+		case line == PGP_ARMOR_END_KEYS:
+			if !inside {
+				return nil, fmt.Errorf("unexpected end of armor")
+			}
+			data, err := base64.StdEncoding.DecodeString(body.String())
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, data)
+			inside = false
+		case !inside:
+		case header:
+			// Armor headers end with an empty line, but may be missing.
+			if line == "" {
+				header = false
+			} else if !strings.Contains(line, ": ") {
+				header = false
+				body.WriteString(line)
+			}
+		case strings.HasPrefix(line, "="):
+			// The CRC24 checksum, base64 itself protects us well enough.
+		default:
+			body.WriteString(line)
+		}
+	}
+	if inside {
+		return nil, fmt.Errorf("unterminated armor")
+	}
+	return blocks, nil
+}
+
+func loadPgpKeys(file string) ([]PgpKey, error) {
+
+	var (
+		keys   []PgpKey
+		blocks [][]byte
+		err    error
+	)
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Contains(content, []byte(PGP_ARMOR_BEGIN_KEYS)) {
+		blocks, err = decodePgpArmor(content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", file, err.Error())
+		}
+	} else {
+		blocks = [][]byte{content}
+	}
+	for _, block := range blocks {
+		packets, err := readPgpPackets(block)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", file, err.Error())
+		}
+		for len(packets) > 0 {
+			n := 1
+			for n < len(packets) && packets[n].Tag != PGP_TAG_PUBLIC_KEY {
+				n++
+			}
+			if packets[0].Tag == PGP_TAG_PUBLIC_KEY {
+				keys = append(keys, readPgpCertificate(packets[:n], time.Now())...)
+			}
+			packets = packets[n:]
+		}
+	}
+	return keys, nil
+}
+
+func loadKeyring(paths []string) ([]PgpKey, error) {
+
+	var keyring []PgpKey
+
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		files := []string{p}
+		if info.IsDir() {
+			entries, err := os.ReadDir(p)
+			if err != nil {
+				return nil, err
+			}
+			files = nil
+			for _, entry := range entries {
+				if !entry.IsDir() {
+					files = append(files, filepath.Join(p, entry.Name()))
+				}
+			}
+		}
+		for _, file := range files {
+			keys, err := loadPgpKeys(file)
+			if err != nil {
+				return nil, err
+			}
+			keyring = append(keyring, keys...)
+		}
+	}
+	return keyring, nil
+}
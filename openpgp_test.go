@@ -0,0 +1,348 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	testKeyIdEd25519 = "6c9cb7ff0bf87d75"
+	testKeyIdRsa     = "731371a3b7dd5bb7"
+	testKeyIdEcdsa   = "94af1d9ada4ff44a"
+	testKeyIdDsa     = "0a73b3c438e303fc"
+	testKeyIdSubkey  = "dc7d8e0c20523ec3"
+)
+
+func loadTestKeyring(t *testing.T) []PgpKey {
+
+	keyring, err := loadKeyring([]string{testKeyring})
+	if err != nil {
+		t.Fatalf("loadKeyring: %s", err.Error())
+	}
+	return keyring
+}
+
+func TestLoadPgpKeys(t *testing.T) {
+
+	keyring := loadTestKeyring(t)
+	want := map[string]string{
+		testKeyIdEd25519: "pulp-admin test (ed25519) <test@example.invalid>",
+		testKeyIdRsa:     "pulp-admin test (rsa) <test@example.invalid>",
+		testKeyIdEcdsa:   "pulp-admin test (ecdsa) <test@example.invalid>",
+		testKeyIdDsa:     "pulp-admin test (dsa) <test@example.invalid>",
+	}
+	if len(keyring) != len(want) {
+		t.Fatalf("%d keys loaded, want %d", len(keyring), len(want))
+	}
+	for _, key := range keyring {
+		id := fmt.Sprintf("%016x", key.KeyId)
+		if want[id] != key.UserId {
+			t.Errorf("key %s has user id %q, want %q", id, key.UserId, want[id])
+		}
+	}
+}
+
+func TestLoadPgpKeysSubkeys(t *testing.T) {
+
+	keyring, err := loadKeyring([]string{testKeyringSubkeys})
+	if err != nil {
+		t.Fatalf("loadKeyring: %s", err.Error())
+	}
+	if len(keyring) != 1 || fmt.Sprintf("%016x", keyring[0].KeyId) != testKeyIdSubkey {
+		for _, key := range keyring {
+			t.Errorf("key %016x %q loaded", key.KeyId, key.UserId)
+		}
+		t.Fatalf("%d keys loaded, want only subkey %s", len(keyring), testKeyIdSubkey)
+	}
+	if keyring[0].UserId != "pulp-admin test (subkeys) <test@example.invalid>" {
+		t.Errorf("subkey has user id %q", keyring[0].UserId)
+	}
+	tests := []struct {
+		file  string
+		known bool
+	}{
+		{testRpmSubkey, true},
+		{testRpmRevokedSubkey, false},
+		{testRpmExpiredSubkey, false},
+	}
+	for _, test := range tests {
+		pkg, err := readRpmPackage(test.file, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature := verifyRpmSignature(test.file, pkg, keyring)
+		if !signature.Signed || signature.Known != test.known || signature.Err != nil {
+			t.Errorf("%s: signed %t, known %t, error %v", test.file, signature.Signed, signature.Known, signature.Err)
+		}
+	}
+}
+
+// testPgpKeyFile writes packets to a key file, in the new packet format.
+func testPgpKeyFile(t *testing.T, packets []PgpPacket) string {
+
+	var data []byte
+
+	for _, packet := range packets {
+		header := []byte{0xc0 | byte(packet.Tag), 0xff, 0, 0, 0, 0}
+		binary.BigEndian.PutUint32(header[2:], uint32(len(packet.Body)))
+		data = append(data, header...)
+		data = append(data, packet.Body...)
+	}
+	file := filepath.Join(t.TempDir(), "keys.gpg")
+	err := os.WriteFile(file, data, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoadPgpKeysUnbound(t *testing.T) {
+
+	content, err := os.ReadFile(testKeyringSubkeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks, err := decodePgpArmor(content)
+	if err != nil || len(blocks) != 1 {
+		t.Fatalf("decodePgpArmor: %d blocks, error %v", len(blocks), err)
+	}
+	packets, err := readPgpPackets(blocks[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := loadPgpKeys(testPgpKeyFile(t, packets))
+	if err != nil || len(keys) != 1 {
+		t.Fatalf("%d keys loaded as is, error %v", len(keys), err)
+	}
+	// The subkey is followed by its binding signature.
+	var subkey int
+	for i, packet := range packets {
+		if packet.Tag == PGP_TAG_PUBLIC_SUB {
+			key, err := readPgpPublicKey(packet.Body)
+			if err == nil && fmt.Sprintf("%016x", key.KeyId) == testKeyIdSubkey {
+				subkey = i
+			}
+		}
+	}
+	if subkey == 0 || packets[subkey+1].Tag != PGP_TAG_SIGNATURE {
+		t.Fatalf("subkey %s and its binding not found", testKeyIdSubkey)
+	}
+	binding := packets[subkey+1]
+	var without, moved, tampered []PgpPacket
+	without = append(without, packets[:subkey+1]...)
+	without = append(without, packets[subkey+2:]...)
+	// Bound to the primary key, but not to this subkey.
+	moved = append(moved, packets[:subkey]...)
+	moved = append(moved, packets[subkey+2], binding, packets[subkey])
+	moved = append(moved, packets[subkey+3:]...)
+	tampered = append(tampered, packets...)
+	body := append([]byte{}, binding.Body...)
+	body[len(body)-1] ^= 0xff
+	tampered[subkey+1] = PgpPacket{Tag: PGP_TAG_SIGNATURE, Body: body}
+	for name, variant := range map[string][]PgpPacket{"without binding": without, "moved binding": moved, "tampered binding": tampered} {
+		keys, err := loadPgpKeys(testPgpKeyFile(t, variant))
+		if err != nil || len(keys) != 0 {
+			t.Errorf("%s: %d keys loaded, error %v", name, len(keys), err)
+		}
+	}
+}
+
+func TestVerifyRpmSignature(t *testing.T) {
+
+	keyring := loadTestKeyring(t)
+	tests := []struct {
+		file  string
+		keyId string
+	}{
+		{testRpmEd25519, testKeyIdEd25519},
+		{testRpmRsa, testKeyIdRsa},
+		{testRpmEcdsa, testKeyIdEcdsa},
+		{testRpmDsa, testKeyIdDsa},
+	}
+	for _, test := range tests {
+		pkg, err := readRpmPackage(test.file, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature := verifyRpmSignature(test.file, pkg, keyring)
+		if !signature.Signed || !signature.Known || signature.Err != nil {
+			t.Errorf("%s: signed %t, known %t, error %v", test.file, signature.Signed, signature.Known, signature.Err)
+		}
+		if signature.KeyId != test.keyId {
+			t.Errorf("%s: signed by %s, want %s", test.file, signature.KeyId, test.keyId)
+		}
+		// Without its key, the signature can't be verified.
+		var others []PgpKey
+		for _, key := range keyring {
+			if fmt.Sprintf("%016x", key.KeyId) != test.keyId {
+				others = append(others, key)
+			}
+		}
+		signature = verifyRpmSignature(test.file, pkg, others)
+		if !signature.Signed || signature.Known {
+			t.Errorf("%s: signed %t, known %t without its key", test.file, signature.Signed, signature.Known)
+		}
+	}
+}
+
+func TestVerifyRpmSignatureWeakHash(t *testing.T) {
+
+	keyring := loadTestKeyring(t)
+	pkg, err := readRpmPackage(testRpmSha1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := verifyRpmSignature(testRpmSha1, pkg, keyring)
+	if !signature.Known || signature.Err == nil {
+		t.Errorf("SHA1 signature: known %t, error %v", signature.Known, signature.Err)
+	}
+	if checkSignaturePolicy(testRpmSha1, SIG_POLICY_REJECT_UNSIGNED, signature) == nil {
+		t.Errorf("SHA1 signature accepted by policy %s", SIG_POLICY_REJECT_UNSIGNED)
+	}
+	if checkSignaturePolicy(testRpmSha1, SIG_POLICY_WARN, signature) != nil {
+		t.Errorf("SHA1 signature rejected by policy %s", SIG_POLICY_WARN)
+	}
+}
+
+func TestVerifyRpmSignatureTampered(t *testing.T) {
+
+	keyring := loadTestKeyring(t)
+	content, err := os.ReadFile(testRpmEd25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content[len(content)-1] ^= 0xff
+	file := filepath.Join(t.TempDir(), "tampered.rpm")
+	err = os.WriteFile(file, content, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := readRpmPackage(file, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := verifyRpmSignature(file, pkg, keyring)
+	if !signature.Known || signature.Err == nil {
+		t.Errorf("tampered payload: known %t, error %v", signature.Known, signature.Err)
+	}
+	// The signature of only the header still holds.
+	var index []RpmHeaderEntry
+	for _, e := range pkg.Signature.Index {
+		if e.Tag != RPMSIGTAG_PGP {
+			index = append(index, e)
+		}
+	}
+	pkg.Signature.Index = index
+	signature = verifyRpmSignature(file, pkg, keyring)
+	if !signature.Known || signature.Err != nil {
+		t.Errorf("header signature: known %t, error %v", signature.Known, signature.Err)
+	}
+}
+
+func TestReadPgpMalformed(t *testing.T) {
+
+	pkg, err := readRpmPackage(testRpmRsa, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob := rpmHeaderBin(pkg.Signature, RPMSIGTAG_PGP)
+	packets, err := readPgpPackets(blob)
+	if err != nil || len(packets) != 1 {
+		t.Fatalf("readPgpPackets: %d packets, error %v", len(packets), err)
+	}
+	for n := 0; n < len(blob); n++ {
+		_, err = readPgpPackets(blob[:n])
+		if n > 0 && err == nil {
+			t.Errorf("signature packet truncated to %d bytes accepted", n)
+		}
+	}
+	body := packets[0].Body
+	for n := 0; n < len(body); n++ {
+		// Only the signature material itself may be cut short, that is
+		// left to the verification.
+		sig, err := readPgpSignature(body[:n])
+		if err == nil && len(sig.Left16) != 2 {
+			t.Errorf("signature truncated to %d bytes accepted", n)
+		}
+	}
+	keyring := loadTestKeyring(t)
+	sig, err := readPgpSignature(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keyring {
+		if key.KeyId != sig.KeyId {
+			continue
+		}
+		for n := 0; n < len(sig.Material); n++ {
+			truncated := sig
+			truncated.Material = sig.Material[:n]
+			h, err := pgpSignatureHash(truncated)
+			if err != nil {
+				t.Fatal(err)
+			}
+			h.Write(pkg.Header.Raw)
+			if verifyPgpSignature(truncated, key, h) == nil {
+				t.Errorf("signature material truncated to %d bytes verified", n)
+			}
+		}
+	}
+	content, err := os.ReadFile(testKeyring)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks, err := decodePgpArmor(content)
+	if err != nil || len(blocks) != 1 {
+		t.Fatalf("decodePgpArmor: %d blocks, error %v", len(blocks), err)
+	}
+	packets, err = readPgpPackets(blocks[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, packet := range packets {
+		if packet.Tag != PGP_TAG_PUBLIC_KEY {
+			continue
+		}
+		for n := 0; n < len(packet.Body); n++ {
+			_, err = readPgpPublicKey(packet.Body[:n])
+			if err == nil {
+				t.Errorf("public key truncated to %d of %d bytes accepted", n, len(packet.Body))
+			}
+		}
+	}
+}
+
+func TestCheckSignaturePolicy(t *testing.T) {
+
+	unsigned := RpmSignature{Signed: false}
+	unknown := RpmSignature{Signed: true, Known: false, KeyId: testKeyIdRsa}
+	bad := RpmSignature{Signed: true, Known: true, KeyId: testKeyIdRsa, Err: os.ErrInvalid}
+	good := RpmSignature{Signed: true, Known: true, KeyId: testKeyIdRsa}
+	tests := []struct {
+		policy    string
+		signature RpmSignature
+		reject    bool
+	}{
+		{SIG_POLICY_WARN, unsigned, false},
+		{SIG_POLICY_WARN, unknown, false},
+		{SIG_POLICY_WARN, bad, false},
+		{SIG_POLICY_WARN, good, false},
+		{SIG_POLICY_REJECT_UNSIGNED, unsigned, true},
+		{SIG_POLICY_REJECT_UNSIGNED, unknown, false},
+		{SIG_POLICY_REJECT_UNSIGNED, bad, true},
+		{SIG_POLICY_REJECT_UNSIGNED, good, false},
+		{SIG_POLICY_REJECT_UNKNOWN, unsigned, true},
+		{SIG_POLICY_REJECT_UNKNOWN, unknown, true},
+		{SIG_POLICY_REJECT_UNKNOWN, bad, true},
+		{SIG_POLICY_REJECT_UNKNOWN, good, false},
+	}
+	for _, test := range tests {
+		err := checkSignaturePolicy("test.rpm", test.policy, test.signature)
+		if (err != nil) != test.reject {
+			t.Errorf("policy %s, signature %+v: error %v", test.policy, test.signature, err)
+		}
+	}
+}
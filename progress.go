@@ -0,0 +1,263 @@
+/* Pulp CLI
+ *
+ * - Version 1.7.0 - 2026/10/19
+ */
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Concurrent uploads share a single progress line on a terminal, so all
+// progress state and all output of the renderer is guarded by this lock.
+var (
+	progressLock     sync.Mutex
+	progressActive   []*Progress
+	progressFinished int
+	progressDrawn    time.Time
+)
+
+func progressIsTTY() bool {
+
+	// CI systems often allocate a pseudo terminal, but their logs can't
+	// handle a line that keeps being rewritten.
+	if os.Getenv("CI") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func newProgress(label string, total int64, initial int64) *Progress {
+
+	now := time.Now()
+	p := &Progress{
+		label:   label,
+		total:   total,
+		done:    initial,
+		initial: initial,
+		message: "",
+		start:   now,
+		last:    time.Time{},
+		tty:     progressIsTTY(),
+	}
+	progressLock.Lock()
+	progressActive = append(progressActive, p)
+	progressLock.Unlock()
+	return p
+}
+
+func formatBytes(n int64) string {
+
+	value := float64(n)
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	i := 0
+	for value >= 1024 && i < len(units)-1 {
+		value /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d %s", n, units[i])
+	}
+	return fmt.Sprintf("%.1f %s", value, units[i])
+}
+
+func progressLine(p *Progress) string {
+
+	if p.total == 0 && p.message != "" {
+		// Task progress, no bytes involved.
+		return fmt.Sprintf("%s: %s [%s]", p.label, p.message, time.Since(p.start).Round(time.Second))
+	}
+	line := fmt.Sprintf("%s: %s", p.label, formatBytes(p.done))
+	if p.total > 0 {
+		line += fmt.Sprintf(" / %s (%d%%)", formatBytes(p.total), p.done*100/p.total)
+	}
+	rate := progressRate(p)
+	if rate > 0 {
+		line += fmt.Sprintf(" %s/s", formatBytes(int64(rate)))
+		if p.total > 0 && p.done < p.total {
+			eta := time.Duration(float64(p.total-p.done)/rate) * time.Second
+			line += fmt.Sprintf(" ETA %s", eta.Round(time.Second))
+		}
+	}
+	return line
+}
+
+func progressRate(p *Progress) float64 {
+
+	elapsed := time.Since(p.start).Seconds()
+	if elapsed <= 0 || p.done <= p.initial {
+		return 0
+	}
+	return float64(p.done-p.initial) / elapsed
+}
+
+func progressSummary() string {
+
+	// More than one line can't be updated in place, so all active progress is
+	// summed up in a single one.
+	var (
+		done  int64
+		total int64
+		rate  float64
+	)
+
+	known := true
+	for _, p := range progressActive {
+		done += p.done
+		total += p.total
+		rate += progressRate(p)
+		if p.total == 0 {
+			known = false
+		}
+	}
+	line := fmt.Sprintf("%d active, %d finished: %s", len(progressActive), progressFinished, formatBytes(done))
+	if known && total > 0 {
+		line += fmt.Sprintf(" / %s (%d%%)", formatBytes(total), done*100/total)
+	}
+	if rate > 0 {
+		line += fmt.Sprintf(" %s/s", formatBytes(int64(rate)))
+		if known && done < total {
+			eta := time.Duration(float64(total-done)/rate) * time.Second
+			line += fmt.Sprintf(" ETA %s", eta.Round(time.Second))
+		}
+	}
+	return line
+}
+
+func progressDraw() {
+
+	// The caller must hold progressLock.
+	progressDrawn = time.Now()
+	switch len(progressActive) {
+	case 0:
+		fmt.Printf("\r\033[K")
+	case 1:
+		fmt.Printf("\r\033[K%s", progressLine(progressActive[0]))
+	default:
+		fmt.Printf("\r\033[K%s", progressSummary())
+	}
+}
+
+func progressRender(p *Progress, final bool) {
+
+	// On a terminal a single line is updated in place, otherwise a log line is
+	// written every PROGRESS_INTERVAL. The caller must hold progressLock.
+	if p.tty {
+		if final {
+			// A finished progress is logged above the line in progress.
+			fmt.Printf("\r\033[K%s\n", progressLine(p))
+			progressDraw()
+			return
+		}
+		if time.Since(progressDrawn) >= 200*time.Millisecond {
+			progressDraw()
+		}
+		return
+	}
+	if !final && time.Since(p.last) < PROGRESS_INTERVAL {
+		return
+	}
+	p.last = time.Now()
+	fmt.Printf("%s\n", progressLine(p))
+}
+
+func progressAdd(p *Progress, n int64) {
+
+	if p == nil {
+		return
+	}
+	progressLock.Lock()
+	p.done += n
+	progressRender(p, false)
+	progressLock.Unlock()
+}
+
+func progressMessage(p *Progress, message string) {
+
+	if p == nil {
+		return
+	}
+	progressLock.Lock()
+	changed := p.message != message
+	p.message = message
+	// A changed message is worth showing right away.
+	if changed {
+		p.last = time.Time{}
+		progressDrawn = time.Time{}
+	}
+	progressRender(p, false)
+	progressLock.Unlock()
+}
+
+func progressLog(p *Progress, format string, a ...interface{}) {
+
+	progressLock.Lock()
+	defer progressLock.Unlock()
+	if p != nil && p.tty {
+		fmt.Printf("\r\033[K")
+	}
+	fmt.Printf(format, a...)
+	if p != nil && p.tty {
+		progressDraw()
+	}
+}
+
+func progressDone(p *Progress) {
+
+	if p == nil {
+		return
+	}
+	progressLock.Lock()
+	defer progressLock.Unlock()
+	for i, active := range progressActive {
+		if active == p {
+			progressActive = append(progressActive[:i], progressActive[i+1:]...)
+			progressFinished++
+			if len(progressActive) == 0 {
+				progressFinished = 0
+			}
+			progressRender(p, true)
+			return
+		}
+	}
+}
+
+func (r *ProgressReader) Read(b []byte) (int, error) {
+
+	n, err := r.reader.Read(b)
+	r.count += int64(n)
+	progressAdd(r.progress, int64(n))
+	return n, err
+}
+
+func newProgressReader(p *Progress, reader io.Reader) *ProgressReader {
+
+	return &ProgressReader{
+		reader:   reader,
+		progress: p,
+		count:    0,
+	}
+}
+
+func taskProgressMessage(reports []ProgressReport) string {
+
+	var parts []string
+
+	for _, report := range reports {
+		if report.Total > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d/%d", report.Message, report.Done, report.Total))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s %d", report.Message, report.Done))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
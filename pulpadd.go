@@ -1,11 +1,14 @@
 /* Pulp CLI
  *
- * - Version 1.1.1 - 2021/08/27
+ * - Version 1.15.0 - 2026/10/19
  */
 package main
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,12 +16,13 @@ import (
 	"mime/multipart"
 	"net/http"
 	"os"
-	"path"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 )
 
-func pulpCreateArtifact(pack string) (PulpCreate, error) {
+func pulpCreateArtifact(pack string, sha256sum string) (PulpCreate, error) {
 
 	var pc = PulpCreate{
 		Pulp_href:    "",
@@ -33,36 +37,67 @@ func pulpCreateArtifact(pack string) (PulpCreate, error) {
 		Sha512:       "",
 	}
 
-	fileDir, err := os.Getwd()
-	if err != nil {
-		return pc, err
-	}
-	filePath := path.Join(fileDir, pack)
-	file, err := os.Open(filePath)
+	file, err := os.Open(pack)
 	if err != nil {
 		return pc, err
 	}
 	defer file.Close()
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	part, err := writer.CreateFormFile("file", filepath.Base(file.Name()))
+	// The multipart body is streamed through a pipe, so the package never
+	// has to be held in memory. The checksum is calculated again while
+	// sending and compared to the one read before, so a package changing
+	// in between is not uploaded under a wrong checksum.
+	reader, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	streamed := make(chan string, 1)
+	info, err := file.Stat()
 	if err != nil {
 		return pc, err
 	}
-	io.Copy(part, file)
-	writer.Close()
-	req, err := http.NewRequest("POST", apiEnd+"/artifacts/", body)
+	progress := newProgress("Uploading "+filepath.Base(pack), info.Size(), 0)
+	defer progressDone(progress)
+	go func() {
+		h := sha256.New()
+		part, err := form.CreateFormFile("file", filepath.Base(file.Name()))
+		if err != nil {
+			streamed <- ""
+			writer.CloseWithError(err)
+			return
+		}
+		_, err = io.Copy(part, io.TeeReader(newProgressReader(progress, file), h))
+		if err != nil {
+			streamed <- ""
+			writer.CloseWithError(err)
+			return
+		}
+		sum := hex.EncodeToString(h.Sum(nil))
+		streamed <- sum
+		// Pulp verifies the bytes it received against this checksum.
+		err = form.WriteField("sha256", sum)
+		if err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+		writer.CloseWithError(form.Close())
+	}()
+	req, err := http.NewRequest("POST", apiEnd+"/artifacts/", reader)
 	if err != nil {
+		reader.Close()
 		return pc, err
 	}
-	req.Header.Add("Content-Type", writer.FormDataContentType())
+	req.Header.Add("Content-Type", form.FormDataContentType())
 	result, status, err := pulpExec(req)
+	reader.Close()
+	progressDone(progress)
 	if err != nil {
 		return pc, err
 	}
 	if status != http.StatusCreated {
 		return pc, fmt.Errorf("HTTP response: %d, body: %s", status, string(result))
 	}
+	sum := <-streamed
+	if sum != sha256sum {
+		return pc, fmt.Errorf("%s changed while being uploaded", pack)
+	}
 	err = json.Unmarshal(result, &pc)
 	if err != nil {
 		return pc, err
@@ -70,11 +105,11 @@ func pulpCreateArtifact(pack string) (PulpCreate, error) {
 	return pc, nil
 }
 
-func pulpAddArtifactToContents(details PulpCreate, pack string) ([]string, error) {
+func pulpAddArtifactToContents(details PulpCreate, name string) ([]string, error) {
 
 	artifact := Artifact{
 		Artifact: details.Pulp_href,
-		Rel_path: filepath.Base(pack),
+		Rel_path: name,
 	}
 	body, err := json.Marshal(artifact)
 	if err != nil {
@@ -107,14 +142,14 @@ func pulpAddArtifactToContents(details PulpCreate, pack string) ([]string, error
 	return taskResults.Created_resources, nil
 }
 
-func pulpAddContentsToRepo(repo string, resources []string) error {
+func pulpAddContentsToRepo(repo string, resources []string) (string, error) {
 
 	r, err := pulpRepositoryInfo(repo)
 	if err != nil {
-		return err
+		return "", err
 	}
 	if r.Count == 0 {
-		return fmt.Errorf("repository %s does not exist", repo)
+		return "", fmt.Errorf("repository %s does not exist", repo)
 	}
 	repoResults := r.Results[0]
 	content := AddContentUnits{
@@ -122,34 +157,40 @@ func pulpAddContentsToRepo(repo string, resources []string) error {
 	}
 	body, err := json.Marshal(content)
 	if err != nil {
-		return err
+		return "", err
 	}
 	data := bytes.NewReader(body)
 	req, err := http.NewRequest("POST", apiSrv+repoResults.Pulp_href+"modify/", data)
 	if err != nil {
-		return err
+		return "", err
 	}
 	req.Header.Set("Content-Type", "application/json")
 	result, status, err := pulpExec(req)
 	if err != nil {
-		return err
+		return "", err
 	}
 	if status != http.StatusAccepted {
-		return fmt.Errorf("HTTP response: %d, body: %s", status, string(result))
+		return "", fmt.Errorf("HTTP response: %d, body: %s", status, string(result))
 	}
 	decoder := json.NewDecoder(bytes.NewReader(result))
 	task := Task{}
 	err = decoder.Decode(&task)
 	if err != nil {
-		return err
+		return "", err
 	}
-	_, err = pulpWaitForTask(task)
+	taskResults, err := pulpWaitForTask(task)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	fmt.Printf("Content added to repository.\n")
-	return nil
+	// No new repository version is created when nothing changed.
+	for _, resource := range taskResults.Created_resources {
+		if strings.Contains(resource, "/versions/") {
+			return resource, nil
+		}
+	}
+	return "", nil
 }
 
 func pulpInitUpload(size int64) (PulpUploadResults, error) {
@@ -163,6 +204,7 @@ func pulpInitUpload(size int64) (PulpUploadResults, error) {
 			Pulp_created: "",
 			Size:         0,
 			Completed:    "",
+			Chunks:       []PulpUploadChunk{},
 		}
 	)
 
@@ -208,7 +250,7 @@ func pulpDeinitUpload(pur PulpUploadResults) error {
 	return nil
 }
 
-func pulpFinishUpload(pur PulpUploadResults, pack string) ([]string, error) {
+func pulpFinishUpload(pur PulpUploadResults, sha256 string) ([]string, error) {
 
 	var (
 		finish = UploadFinish{
@@ -219,10 +261,6 @@ func pulpFinishUpload(pur PulpUploadResults, pack string) ([]string, error) {
 		}
 	)
 
-	sha256, err := calcSHA256(pack)
-	if err != nil {
-		return nil, err
-	}
 	finish.Sha256 = sha256
 	body, err := json.Marshal(finish)
 	if err != nil {
@@ -253,219 +291,267 @@ func pulpFinishUpload(pur PulpUploadResults, pack string) ([]string, error) {
 	return taskResults.Created_resources, nil
 }
 
-func pulpChunkThread(c chan int64, t *Thread, f string, s int64, p PulpUploadResults) {
+func pulpUploadChunk(ctx context.Context, client *http.Client, file *os.File, offset, length, size int64, href string, progress *Progress) (bool, error) {
 
-	// c = channel, t = thread, f = file, s = size, p = pulpUploadResults
-	var (
-		offset       int64
-		ok           bool
-		bytesread    int
-		byteswritten int64
-		temp         string
-	)
+	// The chunk is streamed from the file straight into the request
+	// body, so it never has to be held in memory.
+	section := newProgressReader(progress, io.NewSectionReader(file, offset, length))
+	reader, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	finished := make(chan bool)
+	success := false
+	defer func() {
+		reader.Close()
+		<-finished
+		// Whatever was sent of a failed chunk needs to be sent again.
+		if !success {
+			progressAdd(progress, -section.count)
+		}
+	}()
+	go func() {
+		defer close(finished)
+		part, err := form.CreateFormFile("file", fmt.Sprintf("chunk_offset_%d", offset))
+		if err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+		byteswritten, err := io.Copy(part, section)
+		if err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+		if byteswritten != length {
+			writer.CloseWithError(fmt.Errorf("%d bytes expected, but %d bytes written", length, byteswritten))
+			return
+		}
+		writer.CloseWithError(form.Close())
+	}()
+	request, err := http.NewRequestWithContext(ctx, "PUT", apiSrv+href, reader)
+	if err != nil {
+		return false, err
+	}
+	request.Header.Add("Content-Type", form.FormDataContentType())
+	request.Header.Add("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+length-1, size))
+	request.SetBasicAuth(apiUser, apiPass)
+	response, err := client.Do(request)
+	if err != nil {
+		// Network errors are worth a retry, unless we were cancelled.
+		return ctx.Err() == nil, err
+	}
+	defer response.Body.Close()
+	_, err = ioutil.ReadAll(response.Body)
+	if err != nil {
+		return true, err
+	}
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf("HTTP response: %d, expected: %d", response.StatusCode, http.StatusOK)
+		retry := response.StatusCode >= http.StatusInternalServerError ||
+			response.StatusCode == http.StatusTooManyRequests ||
+			response.StatusCode == http.StatusRequestTimeout
+		return retry, err
+	}
+	success = true
+	return false, nil
+}
+
+func pulpChunkWorker(ctx context.Context, c chan int64, pool *ChunkPool, f string, s int64, p PulpUploadResults) {
 
-	// Each thread (goroutine) needs its own filedescriptor in order to
+	// c = channel, pool = worker pool, f = file, s = size, p = pulpUploadResults
+
+	// Each worker (goroutine) needs its own filedescriptor in order to
 	// read in parallel.
 	file, err := os.Open(f)
 	if err != nil {
-		t.mutex.Lock()
-		t.count--
-		// Checking for nil ensures only the first error is logged.
-		if t.err == nil {
-			t.err = err
-		}
-		t.mutex.Unlock()
+		chunkPoolFail(pool, err)
 		return
 	}
 	defer file.Close()
-	// Each thread (goroutine) needs a dedicated http connection, so that
+	// Each worker (goroutine) needs a dedicated http connection, so that
 	// parallel communication is possible.
-	threadClient := &http.Client{Timeout: time.Second * CLIENT_TIMEOUT}
-	defer threadClient.CloseIdleConnections()
-	// A read/write buffer for processing chunks.
-	buffer := make([]byte, CHUNKSIZE)
-	for {
-		offset, ok = <-c
-		if ok {
-			seek, err := file.Seek(offset, 0)
-			if err != nil {
-				t.mutex.Lock()
-				t.count--
-				if t.err == nil {
-					t.err = err
-				}
-				t.mutex.Unlock()
-				break
-			}
-			if seek != offset {
-				t.mutex.Lock()
-				t.count--
-				if t.err == nil {
-					t.err = fmt.Errorf("seek offset %d in %s expected offset %d", seek, f, offset)
-				}
-				t.mutex.Unlock()
-				break
-			}
-			// Reset buffer to its maximum size. In multi-threaded operation,
-			// the buffer might have been shrunk to accommodate the read of
-			// the last part of the file. But it is possible for a non-last
-			// chunk to exist that still needs to be send, so buffer must be
-			// reset!
-			buffer = buffer[0:CHUNKSIZE]
-			bytesread, err = file.Read(buffer)
-			if err != nil {
-				if err != io.EOF {
-					t.mutex.Lock()
-					t.count--
-					if t.err == nil {
-						t.err = err
-					}
-					t.mutex.Unlock()
-					break
-				} else {
-					err = nil
-				}
-			}
-			body := &bytes.Buffer{}
-			writer := multipart.NewWriter(body)
-			temp = fmt.Sprintf("chunk_offset_%d", offset)
-			part, err := writer.CreateFormFile("file", temp)
-			if err != nil {
-				t.mutex.Lock()
-				t.count--
-				if t.err == nil {
-					t.err = err
-				}
-				t.mutex.Unlock()
-				break
-			}
-			// io.Copy will copy the complete contents of buffer. However the buffer is not
-			// always filled to its maximum size. The last chunk of a file is usually smaller
-			// than the chunksize. As a result garbage still in the buffer will also be copied.
-			// To prohibit this, we explicitly need to set the buffer lenght.
-			buffer = buffer[0:bytesread]
-			byteswritten, err = io.Copy(part, bytes.NewReader(buffer))
-			if err != nil {
-				t.mutex.Lock()
-				t.count--
-				if t.err == nil {
-					t.err = err
-				}
-				t.mutex.Unlock()
-				break
-			}
-			if int64(bytesread) != byteswritten {
-				t.mutex.Lock()
-				t.count--
-				if t.err == nil {
-					t.err = fmt.Errorf("%d bytes read, but %d bytes written", bytesread, byteswritten)
-				}
-				t.mutex.Unlock()
-				break
-			}
-			err = writer.Close()
-			if err != nil {
-				t.mutex.Lock()
-				t.count--
-				if t.err == nil {
-					t.err = err
-				}
-				t.mutex.Unlock()
-				break
-			}
-			request, err := http.NewRequest("PUT", apiSrv+p.Pulp_href, body)
-			if err != nil {
-				t.mutex.Lock()
-				t.count--
-				if t.err == nil {
-					t.err = err
-				}
-				t.mutex.Unlock()
-				break
-			}
-			temp = fmt.Sprintf("bytes %d-%d/%d", offset, offset+int64(bytesread)-1, s)
-			request.Header.Add("Content-Type", writer.FormDataContentType())
-			request.Header.Add("Content-Range", temp)
-			request.SetBasicAuth(apiUser, apiPass)
-			response, err := threadClient.Do(request)
-			if err != nil {
-				t.mutex.Lock()
-				t.count--
-				if t.err == nil {
-					t.err = err
-				}
-				t.mutex.Unlock()
+	client := &http.Client{Timeout: time.Second * CLIENT_TIMEOUT}
+	defer client.CloseIdleConnections()
+	for offset := range c {
+		if ctx.Err() != nil {
+			return
+		}
+		// The last chunk of a file is usually smaller than the chunksize.
+		length := apiChunk
+		if offset+length > s {
+			length = s - offset
+		}
+		backoff := RETRY_BACKOFF
+		for attempt := 1; ; attempt++ {
+			retry, err := pulpUploadChunk(ctx, client, file, offset, length, s, p.Pulp_href, pool.progress)
+			if err == nil {
 				break
 			}
-			_, err = ioutil.ReadAll(response.Body)
-			if err != nil {
-				response.Body.Close()
-				t.mutex.Lock()
-				t.count--
-				if t.err == nil {
-					t.err = err
-				}
-				t.mutex.Unlock()
-				break
+			if !retry || attempt == MAX_RETRIES {
+				chunkPoolFail(pool, fmt.Errorf("chunk at offset %d: %s", offset, err.Error()))
+				return
 			}
-			if response.StatusCode != http.StatusOK {
-				response.Body.Close()
-				t.mutex.Lock()
-				t.count--
-				if t.err == nil {
-					t.err = fmt.Errorf("HTTP response: %d, expected: %d", response.StatusCode, http.StatusOK)
-				}
-				t.mutex.Unlock()
-				break
+			progressLog(pool.progress, "chunk at offset %d failed (%s), retrying in %s\n", offset, err.Error(), backoff)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return
 			}
-			response.Body.Close()
-			fmt.Printf("chunk %s uploaded\n", temp)
-		} else {
-			t.mutex.Lock()
-			t.count--
-			t.mutex.Unlock()
-			break
+			backoff *= 2
+		}
+		pool.mutex.Lock()
+		pool.sent += length
+		if pool.state != nil {
+			pool.state.Ranges = append(pool.state.Ranges, UploadRange{
+				Start: offset,
+				End:   offset + length - 1,
+			})
+			err = saveUploadState(pool.state)
+		}
+		pool.mutex.Unlock()
+		if err != nil {
+			chunkPoolFail(pool, err)
+			return
 		}
 	}
 }
 
-func pulpUploadChunks(file string, size int64, pur PulpUploadResults) error {
+func chunkPoolFail(pool *ChunkPool, err error) {
+
+	pool.mutex.Lock()
+	// Checking for nil ensures only the first error is logged.
+	if pool.err == nil {
+		pool.err = err
+	}
+	pool.mutex.Unlock()
+	// The first fatal error stops all other workers.
+	pool.cancel()
+}
+
+func pulpUploadChunks(file string, size int64, pur PulpUploadResults, state *UploadState) error {
 
 	var (
-		offset int64
-		t      Thread
+		wg   sync.WaitGroup
+		pool ChunkPool
 	)
 
-	c := make(chan int64, MAX_THREADS)
-	for i := 0; i < MAX_THREADS; i++ {
-		go pulpChunkThread(c, &t, file, size, pur)
-		t.mutex.Lock()
-		t.count++
-		t.mutex.Unlock()
-	}
-	for offset = 0; offset < size; offset += CHUNKSIZE {
-		t.mutex.Lock()
-		if t.err != nil {
-			t.mutex.Unlock()
-			break
-		}
-		t.mutex.Unlock()
-		c <- offset
-	}
-	close(c) // We're done sending chunks, inform threads to terminate.
-	t.mutex.Lock()
-	for t.count != 0 {
-		t.mutex.Unlock()
-		time.Sleep(time.Second)
-		t.mutex.Lock()
-	}
-	t.mutex.Unlock()
-	return t.err
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.state = state
+	pool.cancel = cancel
+	if state != nil {
+		pool.progress = newProgress("Uploading "+filepath.Base(file), size, uploadedBytes(state))
+	} else {
+		pool.progress = newProgress("Uploading "+filepath.Base(file), size, 0)
+	}
+	c := make(chan int64)
+	for i := 0; i < apiThreads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pulpChunkWorker(ctx, c, &pool, file, size, pur)
+		}()
+	}
+	start := time.Now()
+FEED:
+	for offset := int64(0); offset < size; offset += apiChunk {
+		// Skip the chunks that were uploaded by a previous run.
+		end := offset + apiChunk - 1
+		if end >= size {
+			end = size - 1
+		}
+		pool.mutex.Lock()
+		covered := state != nil && uploadCovers(state, offset, end)
+		pool.mutex.Unlock()
+		if covered {
+			continue
+		}
+		select {
+		case c <- offset:
+		case <-ctx.Done():
+			break FEED
+		}
+	}
+	close(c) // We're done sending chunks, inform workers to terminate.
+	wg.Wait()
+	progressDone(pool.progress)
+	if pool.err != nil {
+		return pool.err
+	}
+	elapsed := time.Since(start)
+	rate := float64(pool.sent) / elapsed.Seconds() / (1024 * 1024)
+	fmt.Printf("%d bytes uploaded in %s (%.2f MiB/s)\n", pool.sent, elapsed.Round(time.Millisecond), rate)
+	return nil
+}
+
+func pulpVerifyArtifact(pc PulpCreate, pkg RpmPackage) error {
+
+	if int64(pc.Size) != pkg.Size {
+		return fmt.Errorf("artifact size %d does not match package size %d", pc.Size, pkg.Size)
+	}
+	if pc.Sha256 != pkg.PkgId {
+		return fmt.Errorf("artifact sha256 %s does not match package sha256 %s", pc.Sha256, pkg.PkgId)
+	}
+	// Pulp only fills in the checksums it is configured to keep.
+	if pc.Sha512 != "" && pc.Sha512 != pkg.Sha512 {
+		return fmt.Errorf("artifact sha512 does not match package sha512")
+	}
+	return nil
+}
+
+func pulpVerifyContent(href string, pkg RpmPackage) error {
+
+	content, err := pulpContentDetails(href)
+	if err != nil {
+		return err
+	}
+	local := rpmNevra(pkg.Name, pkg.Epoch, pkg.Version, pkg.Release, pkg.Arch)
+	epoch := content.Epoch
+	if epoch == "" {
+		epoch = "0"
+	}
+	remote := rpmNevra(content.Name, epoch, content.Version, content.Release, content.Arch)
+	if remote != local {
+		return fmt.Errorf("content unit %s does not match package %s", remote, local)
+	}
+	if content.PkgId != pkg.PkgId {
+		return fmt.Errorf("content unit %s has checksum %s instead of %s", remote, content.PkgId, pkg.PkgId)
+	}
+	return nil
 }
 
-func pulpAddPackage(repo, pack string) error {
+func pulpVerifyRepoVersion(version string, results []PackageUpload) error {
+
+	var missing []string
+
+	for _, r := range results {
+		if r.Err != nil || r.Status == "already present" {
+			continue
+		}
+		content, err := pulpPackageContent(r.Sha256, version)
+		if err != nil {
+			return err
+		}
+		if content.Count == 0 {
+			missing = append(missing, r.Nevra)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("repository version lacks %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func pulpUploadPackage(src PackageSource, opts UploadOptions) PackageUpload {
 
 	var (
+		upload = PackageUpload{
+			File:    src.Origin,
+			Sha256:  "",
+			Nevra:   "",
+			Signer:  "",
+			Status:  "failed",
+			Content: nil,
+			Err:     nil,
+		}
 		pc = PulpCreate{
 			Pulp_href:    "",
 			Pulp_created: "",
@@ -481,69 +567,323 @@ func pulpAddPackage(repo, pack string) error {
 		resources []string
 	)
 
-	results, err := pulpPackageInfo(pack)
+	// Packages coming from an url or stdin are first stored in a
+	// temporary file.
+	if src.Path == "" {
+		err := fetchPackage(&src)
+		if src.Temp {
+			defer os.Remove(src.Path)
+		}
+		if err != nil {
+			upload.Err = err
+			return upload
+		}
+	}
+	// Reading the package validates its header and digests. The checksum
+	// is calculated in the same pass and reused for every step of the
+	// upload. Packages small enough to be uploaded in one piece are hashed
+	// again while being sent, to detect them changing in between.
+	pkg, err := readRpmPackage(src.Path, true)
 	if err != nil {
-		return err
+		upload.Err = err
+		return upload
 	}
-	if results.Count > 0 {
-		return fmt.Errorf("package already exists")
+	upload.Nevra = rpmNevra(pkg.Name, pkg.Epoch, pkg.Version, pkg.Release, pkg.Arch)
+	problem := pkg.DigestErr
+	if problem == nil && opts.Repo != nil {
+		problem = checkRpmRepository(pkg, *opts.Repo)
+	}
+	if problem != nil {
+		if !opts.Force {
+			upload.Err = fmt.Errorf("%s, use -force to add it anyway", problem.Error())
+			return upload
+		}
+		fmt.Printf("WARNING %s: %s\n", src.Origin, problem.Error())
+	}
+	if opts.Policy != SIG_POLICY_OFF {
+		signature := verifyRpmSignature(src.Path, pkg, opts.Keyring)
+		err = checkSignaturePolicy(src.Origin, opts.Policy, signature)
+		if err != nil {
+			upload.Err = err
+			return upload
+		}
+		if signature.Known && signature.Err == nil {
+			upload.Signer = signature.KeyId
+			if signature.UserId != "" {
+				upload.Signer += " (" + signature.UserId + ")"
+			}
+		}
+	}
+	if src.Sha256 == "" {
+		src.Sha256 = pkg.PkgId
+	}
+	upload.Sha256 = src.Sha256
+	// Nothing needs to be done when the latest repository version
+	// already holds the package.
+	if opts.Version != "" {
+		content, err := pulpPackageContent(src.Sha256, opts.Version)
+		if err != nil {
+			upload.Err = err
+			return upload
+		}
+		if content.Count > 0 {
+			upload.Status = "already present"
+			return upload
+		}
 	}
-	fileDir, err := os.Getwd()
+	// The package might have been added to another repository already,
+	// in which case its content unit can simply be reused.
+	content, err := pulpPackageContent(src.Sha256, "")
 	if err != nil {
-		return err
+		upload.Err = err
+		return upload
+	}
+	if content.Count > 0 {
+		err = pulpVerifyContent(content.Results[0].Pulp_href, pkg)
+		if err != nil {
+			upload.Err = err
+			return upload
+		}
+		upload.Status = "reused"
+		upload.Content = []string{content.Results[0].Pulp_href}
+		return upload
+	}
+	// An artifact without a content unit, e.g. left behind by an earlier
+	// failed attempt, doesn't need to be uploaded again.
+	results, err := pulpPackageInfo(src.Sha256)
+	if err != nil {
+		upload.Err = err
+		return upload
+	}
+	if results.Count > 0 {
+		err = pulpVerifyArtifact(results.Results[0], pkg)
+		if err != nil {
+			upload.Err = err
+			return upload
+		}
+		resources, err = pulpAddArtifactToContents(results.Results[0], src.Name)
+		if err != nil {
+			upload.Err = err
+			return upload
+		}
+		err = pulpVerifyContent(resources[0], pkg)
+		if err != nil {
+			upload.Err = err
+			return upload
+		}
+		upload.Status = "reused"
+		upload.Content = resources
+		return upload
 	}
-	file := path.Join(fileDir, pack)
+	file := src.Path
 	size, err := getFileSize(file)
 	if err != nil {
-		return err
+		upload.Err = err
+		return upload
 	}
 	/*
-	 * If the package size is less than CHUNKSIZE, we'll
+	 * If the package size is less than the chunksize, we'll
 	 * perform a direct upload. If not, a chunked upload
 	 * is performed.
 	 */
-	if size > CHUNKSIZE {
-		pur, err := pulpInitUpload(size)
+	if size > apiChunk {
+		var (
+			pur   PulpUploadResults
+			state *UploadState
+		)
+
+		// Uploads of temporary files can never be resumed, so there's no
+		// point in keeping track of their state.
+		if src.Temp {
+			pur, err = pulpInitUpload(size)
+		} else {
+			pur, state, err = pulpResumeUpload(file, size, src.Sha256)
+		}
 		if err != nil {
-			return err
+			upload.Err = err
+			return upload
 		}
-		err = pulpUploadChunks(file, size, pur)
+		err = pulpUploadChunks(file, size, pur, state)
 		if err != nil {
+			if state != nil {
+				upload.Err = fmt.Errorf("%s, rerun 'add' to resume the upload", err.Error())
+				return upload
+			}
 			err2 := pulpDeinitUpload(pur)
 			if err2 != nil {
-				fmt.Printf("ERROR %s\n", err.Error())
-				return err2
+				upload.Err = fmt.Errorf("%s, removing the upload failed: %s", err.Error(), err2.Error())
 			} else {
-				return err
+				upload.Err = err
+			}
+			return upload
+		}
+		resources, err = pulpFinishUpload(pur, src.Sha256)
+		if state != nil {
+			err2 := removeUploadState(state)
+			if err2 != nil {
+				fmt.Printf("WARNING %s\n", err2.Error())
 			}
 		}
-		resources, err = pulpFinishUpload(pur, pack)
 		if err != nil {
 			err2 := pulpDeinitUpload(pur)
 			if err2 != nil {
-				fmt.Printf("ERROR %s\n", err.Error())
-				return err2
+				upload.Err = fmt.Errorf("%s, removing the upload failed: %s", err.Error(), err2.Error())
 			} else {
-				return err
+				upload.Err = err
 			}
+			return upload
 		}
 		pc, err = pulpArtifactInfo(resources[0])
 		if err != nil {
-			return err
+			upload.Err = err
+			return upload
 		}
 	} else {
-		pc, err = pulpCreateArtifact(pack)
+		pc, err = pulpCreateArtifact(file, src.Sha256)
 		if err != nil {
-			return err
+			upload.Err = err
+			return upload
 		}
 	}
-	resources, err = pulpAddArtifactToContents(pc, pack)
+	// An artifact that doesn't hold what we sent is of no use to anyone.
+	err = pulpVerifyArtifact(pc, pkg)
 	if err != nil {
-		return err
+		err2 := pulpDelArtifact(pc.Pulp_href)
+		if err2 != nil {
+			fmt.Printf("WARNING could not remove artifact %s: %s\n", pc.Pulp_href, err2.Error())
+		}
+		upload.Err = err
+		return upload
 	}
-	err = pulpAddContentsToRepo(repo, resources)
+	resources, err = pulpAddArtifactToContents(pc, src.Name)
 	if err != nil {
-		return err
+		upload.Err = err
+		return upload
 	}
-	return nil
+	err = pulpVerifyContent(resources[0], pkg)
+	if err != nil {
+		err2 := pulpOrphanCleanContent(resources)
+		if err2 != nil {
+			fmt.Printf("WARNING could not remove content unit %s: %s\n", resources[0], err2.Error())
+		}
+		upload.Err = err
+		return upload
+	}
+	upload.Status = "uploaded"
+	upload.Content = resources
+	return upload
+}
+
+func pulpAddPackages(repo string, packs []PackageSource, advisories []Advisory, force bool) ([]PackageUpload, error) {
+
+	var (
+		wg      sync.WaitGroup
+		content []string
+		present int
+		failed  int
+	)
+
+	r, err := pulpRepositoryInfo(repo)
+	if err != nil {
+		return nil, err
+	}
+	if r.Count == 0 {
+		return nil, fmt.Errorf("repository %s does not exist", repo)
+	}
+	opts := UploadOptions{
+		Version: r.Results[0].Latest_version_href,
+		Policy:  SIG_POLICY_OFF,
+		Keyring: nil,
+		Repo:    nil,
+		Force:   force,
+	}
+	// Repositories that don't follow the naming rules can't be checked
+	// for the arch and release of their packages.
+	details, err := deconstructRepository(repo)
+	if err == nil {
+		opts.Repo = &details
+	}
+	opts.Policy, err = signaturePolicy(repo)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Policy != SIG_POLICY_OFF {
+		paths, err := keyringPaths()
+		if err != nil {
+			return nil, err
+		}
+		opts.Keyring, err = loadKeyring(paths)
+		if err != nil {
+			return nil, err
+		}
+		if len(opts.Keyring) == 0 {
+			fmt.Printf("WARNING no trusted keys found for signature verification\n")
+		}
+	}
+	// Packages are uploaded concurrently, but all resulting content units
+	// are added to the repository at once, so that only a single new
+	// repository version gets created.
+	results := make([]PackageUpload, len(packs))
+	c := make(chan int, len(packs))
+	for i := range packs {
+		c <- i
+	}
+	close(c)
+	for i := 0; i < MAX_UPLOADS && i < len(packs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range c {
+				results[n] = pulpUploadPackage(packs[n], opts)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, r := range results {
+		if r.Err == nil {
+			content = append(content, r.Content...)
+			if r.Status == "already present" {
+				present++
+			}
+		} else {
+			failed++
+		}
+	}
+	// Advisories go into the same repository version as their packages,
+	// but only when all of those made it.
+	if len(advisories) > 0 {
+		if failed > 0 {
+			return results, fmt.Errorf("nothing added to repository %s, as not all packages of the advisories could be uploaded", repo)
+		}
+		resources, err := pulpUploadAdvisories(advisories)
+		if err != nil {
+			return results, err
+		}
+		content = append(content, resources...)
+	}
+	if len(content) == 0 {
+		if present > 0 {
+			// No need for a new repository version.
+			return results, nil
+		}
+		return results, fmt.Errorf("none of the packages could be added to repository %s", repo)
+	}
+	version, err := pulpAddContentsToRepo(repo, content)
+	if err != nil {
+		return results, err
+	}
+	if version == "" {
+		// Nothing changed, so the repository already held everything.
+		return results, pulpVerifyRepoVersion(opts.Version, results)
+	}
+	// Never publish a repository version that lacks any of the packages.
+	err = pulpVerifyRepoVersion(version, results)
+	if err != nil {
+		err2 := pulpDelRepoVersion(version)
+		if err2 != nil {
+			return results, fmt.Errorf("%s, rollback failed: %s", err.Error(), err2.Error())
+		}
+		return results, fmt.Errorf("%s, repository version rolled back", err.Error())
+	}
+	return results, nil
 }
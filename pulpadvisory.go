@@ -0,0 +1,374 @@
+/* Pulp CLI
+ *
+ * - Version 1.15.0 - 2026/10/19
+ */
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+func updateInfoBool(value *string) bool {
+
+	// Flags like reboot_suggested are either an empty element or contain
+	// something like True, true or 1.
+	if value == nil {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(*value)) {
+	case "", "true", "1", "yes":
+		return true
+	}
+	return false
+}
+
+func convertUpdateInfo(update UpdateInfoUpdate) Advisory {
+
+	adv := Advisory{
+		Pulp_href:        "",
+		Id:               strings.TrimSpace(update.Id),
+		Updated_date:     update.Updated.Date,
+		Issued_date:      update.Issued.Date,
+		Description:      update.Description,
+		Fromstr:          update.From,
+		Status:           update.Status,
+		Title:            update.Title,
+		Summary:          update.Summary,
+		Version:          update.Version,
+		Type:             update.Type,
+		Severity:         update.Severity,
+		Solution:         update.Solution,
+		Release:          update.Release,
+		Rights:           update.Rights,
+		Pushcount:        update.Pushcount,
+		Reboot_suggested: updateInfoBool(update.Reboot_suggested),
+		Pkglist:          []AdvisoryCollection{},
+		References:       []AdvisoryReference{},
+	}
+	for _, ref := range update.References {
+		adv.References = append(adv.References, AdvisoryReference{
+			Href:     ref.Href,
+			Ref_id:   ref.Id,
+			Title:    ref.Title,
+			Ref_type: ref.Type,
+		})
+	}
+	for _, col := range update.Collections {
+		collection := AdvisoryCollection{
+			Name:      col.Name,
+			Shortname: col.Short,
+			Packages:  []AdvisoryPackage{},
+		}
+		for _, pack := range col.Packages {
+			collection.Packages = append(collection.Packages, AdvisoryPackage{
+				Name:             pack.Name,
+				Epoch:            pack.Epoch,
+				Version:          pack.Version,
+				Release:          pack.Release,
+				Arch:             pack.Arch,
+				Src:              pack.Src,
+				Filename:         pack.Filename,
+				Sum:              strings.TrimSpace(pack.Sum.Value),
+				Sum_type:         pack.Sum.Type,
+				Reboot_suggested: updateInfoBool(pack.Reboot_suggested),
+			})
+		}
+		adv.Pkglist = append(adv.Pkglist, collection)
+	}
+	return adv
+}
+
+func readAdvisories(file string) ([]Advisory, error) {
+
+	var advisories []Advisory
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	content = bytes.TrimSpace(content)
+	if len(content) == 0 {
+		return nil, fmt.Errorf("%s is empty", file)
+	}
+	switch content[0] {
+	case '<':
+		info := UpdateInfo{
+			Updates: []UpdateInfoUpdate{},
+		}
+		err = xml.Unmarshal(content, &info)
+		if err != nil {
+			// A file might also hold a single update, without the
+			// enclosing updates element.
+			update := UpdateInfoUpdate{}
+			err2 := xml.Unmarshal(content, &update)
+			if err2 != nil || update.Id == "" {
+				return nil, fmt.Errorf("%s: %s", file, err.Error())
+			}
+			info.Updates = append(info.Updates, update)
+		}
+		for _, update := range info.Updates {
+			advisories = append(advisories, convertUpdateInfo(update))
+		}
+	case '[':
+		err = json.Unmarshal(content, &advisories)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", file, err.Error())
+		}
+	case '{':
+		adv := Advisory{}
+		err = json.Unmarshal(content, &adv)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", file, err.Error())
+		}
+		advisories = append(advisories, adv)
+	default:
+		return nil, fmt.Errorf("%s is neither an updateinfo xml nor a json file", file)
+	}
+	if len(advisories) == 0 {
+		return nil, fmt.Errorf("%s holds no advisories", file)
+	}
+	for _, adv := range advisories {
+		if adv.Id == "" {
+			return nil, fmt.Errorf("%s holds an advisory without id", file)
+		}
+	}
+	return advisories, nil
+}
+
+func pulpAdvisoryInfo(id string, version string) (PulpAdvisoryResults, error) {
+
+	var r = PulpAdvisoryResults{
+		Count:    0,
+		Next:     "",
+		Previous: "",
+		Results:  []Advisory{},
+	}
+
+	requestString := apiEnd + "/content/rpm/advisories/?id=" + url.QueryEscape(id)
+	if version != "" {
+		requestString += "&repository_version=" + url.QueryEscape(version)
+	}
+	req, err := http.NewRequest("GET", requestString, nil)
+	if err != nil {
+		return r, err
+	}
+	body, status, err := pulpExec(req)
+	if err != nil {
+		return r, err
+	}
+	if status != http.StatusOK {
+		return r, fmt.Errorf("HTTP response: %d, expected: %d", status, http.StatusOK)
+	}
+	err = json.Unmarshal(body, &r)
+	if err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+func pulpUploadAdvisory(adv Advisory) (string, error) {
+
+	// An identical advisory, e.g. added to another repository, is reused.
+	info, err := pulpAdvisoryInfo(adv.Id, "")
+	if err != nil {
+		return "", err
+	}
+	for _, existing := range info.Results {
+		if existing.Updated_date == adv.Updated_date {
+			fmt.Printf("Advisory %s reused.\n", adv.Id)
+			return existing.Pulp_href, nil
+		}
+	}
+	adv.Pulp_href = ""
+	content, err := json.Marshal(adv)
+	if err != nil {
+		return "", err
+	}
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", adv.Id+".json")
+	if err != nil {
+		return "", err
+	}
+	_, err = part.Write(content)
+	if err != nil {
+		return "", err
+	}
+	err = form.Close()
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest("POST", apiEnd+"/content/rpm/advisories/", &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Add("Content-Type", form.FormDataContentType())
+	result, status, err := pulpExec(req)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusAccepted {
+		return "", fmt.Errorf("HTTP response: %d, body: %s", status, string(result))
+	}
+	task := Task{}
+	err = json.Unmarshal(result, &task)
+	if err != nil {
+		return "", err
+	}
+	taskResults, err := pulpWaitForTask(task)
+	if err != nil {
+		return "", err
+	}
+	for _, resource := range taskResults.Created_resources {
+		if strings.Contains(resource, "/content/rpm/advisories/") {
+			fmt.Printf("Advisory %s uploaded.\n", adv.Id)
+			return resource, nil
+		}
+	}
+	return "", fmt.Errorf("advisory %s was not created", adv.Id)
+}
+
+func pulpUploadAdvisories(advisories []Advisory) ([]string, error) {
+
+	var resources []string
+
+	for _, adv := range advisories {
+		href, err := pulpUploadAdvisory(adv)
+		if err != nil {
+			return nil, fmt.Errorf("advisory %s: %s", adv.Id, err.Error())
+		}
+		resources = append(resources, href)
+	}
+	return resources, nil
+}
+
+func pulpAddAdvisories(repo string, advisories []Advisory) error {
+
+	resources, err := pulpUploadAdvisories(advisories)
+	if err != nil {
+		return err
+	}
+	_, err = pulpAddContentsToRepo(repo, resources)
+	return err
+}
+
+func pulpAdvisoryList(repo string) ([]Advisory, error) {
+
+	var (
+		advInfo PulpAdvisoryResults
+		results []Advisory
+	)
+
+	repoInfo, err := pulpRepositoryInfo(repo)
+	if err != nil {
+		return nil, err
+	}
+	if repoInfo.Count == 0 {
+		return nil, fmt.Errorf("repository %s does not exist", repo)
+	}
+	version := repoInfo.Results[0].Latest_version_href
+	requestString := apiEnd + "/content/rpm/advisories/?repository_version=" + url.QueryEscape(version)
+	for requestString != "" {
+		req, err := http.NewRequest("GET", requestString, nil)
+		if err != nil {
+			return nil, err
+		}
+		body, status, err := pulpExec(req)
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("HTTP response: %d", status)
+		}
+		advInfo = PulpAdvisoryResults{
+			Count:    0,
+			Next:     "",
+			Previous: "",
+			Results:  []Advisory{},
+		}
+		err = json.Unmarshal(body, &advInfo)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, advInfo.Results...)
+		requestString = advInfo.Next
+	}
+	return results, nil
+}
+
+func pulpAdvisoryShow(repo string, id string) (Advisory, error) {
+
+	repoInfo, err := pulpRepositoryInfo(repo)
+	if err != nil {
+		return Advisory{}, err
+	}
+	if repoInfo.Count == 0 {
+		return Advisory{}, fmt.Errorf("repository %s does not exist", repo)
+	}
+	info, err := pulpAdvisoryInfo(id, repoInfo.Results[0].Latest_version_href)
+	if err != nil {
+		return Advisory{}, err
+	}
+	if info.Count == 0 {
+		return Advisory{}, fmt.Errorf("advisory %s not found in repository %s", id, repo)
+	}
+	return info.Results[0], nil
+}
+
+func pulpDelAdvisories(repo string, ids []string) error {
+
+	var remove []string
+
+	repoInfo, err := pulpRepositoryInfo(repo)
+	if err != nil {
+		return err
+	}
+	if repoInfo.Count == 0 {
+		return fmt.Errorf("repository %s does not exist", repo)
+	}
+	for _, id := range ids {
+		info, err := pulpAdvisoryInfo(id, repoInfo.Results[0].Latest_version_href)
+		if err != nil {
+			return err
+		}
+		if info.Count == 0 {
+			return fmt.Errorf("advisory %s not found in repository %s", id, repo)
+		}
+		remove = append(remove, info.Results[0].Pulp_href)
+	}
+	return pulpRemoveContentsFromRepo(repo, remove)
+}
+
+func printAdvisory(adv Advisory) {
+
+	fmt.Printf("Id:          %s\n", adv.Id)
+	fmt.Printf("Title:       %s\n", adv.Title)
+	fmt.Printf("Type:        %s\n", adv.Type)
+	fmt.Printf("Severity:    %s\n", adv.Severity)
+	fmt.Printf("Status:      %s\n", adv.Status)
+	fmt.Printf("Issued:      %s\n", adv.Issued_date)
+	fmt.Printf("Updated:     %s\n", adv.Updated_date)
+	fmt.Printf("Reboot:      %t\n", adv.Reboot_suggested)
+	if adv.Summary != "" {
+		fmt.Printf("Summary:     %s\n", adv.Summary)
+	}
+	if adv.Description != "" {
+		fmt.Printf("Description:\n%s\n", strings.TrimSpace(adv.Description))
+	}
+	for _, ref := range adv.References {
+		fmt.Printf("Reference:   %s %s\n", ref.Ref_id, ref.Href)
+	}
+	for _, col := range adv.Pkglist {
+		for _, pack := range col.Packages {
+			fmt.Printf("Package:     %s\n", rpmNevra(pack.Name, pack.Epoch, pack.Version, pack.Release, pack.Arch))
+		}
+	}
+}
@@ -0,0 +1,208 @@
+/* Pulp CLI
+ *
+ * - Version 1.13.0 - 2026/10/19
+ */
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+var compsKinds = []struct {
+	kind     string
+	endpoint string
+}{
+	{"group", "/content/rpm/packagegroups/"},
+	{"category", "/content/rpm/packagecategories/"},
+	{"environment", "/content/rpm/packageenvironments/"},
+}
+
+func pulpUploadComps(repo string, comps string, replace bool) error {
+
+	repoInfo, err := pulpRepositoryInfo(repo)
+	if err != nil {
+		return err
+	}
+	if repoInfo.Count == 0 {
+		return fmt.Errorf("repository %s does not exist", repo)
+	}
+	file, err := os.Open(comps)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filepath.Base(comps))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, file)
+	if err != nil {
+		return err
+	}
+	// With a repository, Pulp adds the comps content to a new repository
+	// version itself. Replace removes the comps content that was there.
+	err = form.WriteField("repository", repoInfo.Results[0].Pulp_href)
+	if err != nil {
+		return err
+	}
+	err = form.WriteField("replace", strconv.FormatBool(replace))
+	if err != nil {
+		return err
+	}
+	err = form.Close()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", apiEnd+"/rpm/comps/", &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", form.FormDataContentType())
+	result, status, err := pulpExec(req)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusAccepted {
+		return fmt.Errorf("HTTP response: %d, body: %s", status, string(result))
+	}
+	task := Task{}
+	err = json.Unmarshal(result, &task)
+	if err != nil {
+		return err
+	}
+	_, err = pulpWaitForTask(task)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Comps added to repository.\n")
+	return nil
+}
+
+func pulpCompsList(version string, endpoint string) ([]PulpComps, error) {
+
+	var (
+		compsInfo PulpCompsResults
+		results   []PulpComps
+	)
+
+	requestString := apiEnd + endpoint + "?repository_version=" + url.QueryEscape(version)
+	for requestString != "" {
+		req, err := http.NewRequest("GET", requestString, nil)
+		if err != nil {
+			return nil, err
+		}
+		body, status, err := pulpExec(req)
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("HTTP response: %d", status)
+		}
+		compsInfo = PulpCompsResults{
+			Count:    0,
+			Next:     "",
+			Previous: "",
+			Results:  []PulpComps{},
+		}
+		err = json.Unmarshal(body, &compsInfo)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, compsInfo.Results...)
+		requestString = compsInfo.Next
+	}
+	return results, nil
+}
+
+func pulpCompsShow(repo string, version int) error {
+
+	repoInfo, err := pulpRepositoryInfo(repo)
+	if err != nil {
+		return err
+	}
+	if repoInfo.Count == 0 {
+		return fmt.Errorf("repository %s does not exist", repo)
+	}
+	// Without a version, the latest one is shown.
+	versionHref := repoInfo.Results[0].Latest_version_href
+	if version >= 0 {
+		versionHref, err = pulpVersionHref(repoInfo.Results[0], version)
+		if err != nil {
+			return err
+		}
+	}
+	for _, k := range compsKinds {
+		res, err := pulpCompsList(versionHref, k.endpoint)
+		if err != nil {
+			return err
+		}
+		for _, c := range res {
+			switch k.kind {
+			case "group":
+				fmt.Printf("%s\t%s\t%s\t%d packages\n", k.kind, c.Id, c.Name, len(c.Packages))
+			case "category":
+				fmt.Printf("%s\t%s\t%s\t%d groups\n", k.kind, c.Id, c.Name, len(c.Group_ids))
+			case "environment":
+				fmt.Printf("%s\t%s\t%s\t%d groups, %d options\n", k.kind, c.Id, c.Name, len(c.Group_ids), len(c.Option_ids))
+			}
+		}
+	}
+	return nil
+}
+
+func pulpDelGroups(repo string, ids []string) error {
+
+	var (
+		remove    []string
+		compsInfo = PulpCompsResults{
+			Count:    0,
+			Next:     "",
+			Previous: "",
+			Results:  []PulpComps{},
+		}
+	)
+
+	repoInfo, err := pulpRepositoryInfo(repo)
+	if err != nil {
+		return err
+	}
+	if repoInfo.Count == 0 {
+		return fmt.Errorf("repository %s does not exist", repo)
+	}
+	for _, id := range ids {
+		requestString := apiEnd + "/content/rpm/packagegroups/"
+		requestString += "?repository_version=" + url.QueryEscape(repoInfo.Results[0].Latest_version_href)
+		requestString += "&id=" + url.QueryEscape(id)
+		req, err := http.NewRequest("GET", requestString, nil)
+		if err != nil {
+			return err
+		}
+		body, status, err := pulpExec(req)
+		if err != nil {
+			return err
+		}
+		if status != http.StatusOK {
+			return fmt.Errorf("HTTP response: %d", status)
+		}
+		err = json.Unmarshal(body, &compsInfo)
+		if err != nil {
+			return err
+		}
+		if compsInfo.Count == 0 {
+			return fmt.Errorf("group %s not found in repository %s", id, repo)
+		}
+		remove = append(remove, compsInfo.Results[0].Pulp_href)
+	}
+	return pulpRemoveContentsFromRepo(repo, remove)
+}
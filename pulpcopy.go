@@ -0,0 +1,123 @@
+/* Pulp CLI
+ *
+ * - Version 1.23.0 - 2026/10/19
+ */
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func pulpCopyContents(from PulpRepository, version int, args []string, advisories bool) (string, []string, error) {
+
+	// A version of -1 is the latest one.
+	var resources []string
+
+	versionHref := from.Latest_version_href
+	if version >= 0 {
+		href, err := pulpVersionHref(from, version)
+		if err != nil {
+			return "", nil, err
+		}
+		versionHref = href
+	}
+	if !advisories {
+		packs, err := pulpResolveVersionPackages(from.Name, versionHref, args)
+		if err != nil {
+			return "", nil, err
+		}
+		for _, pack := range packs {
+			fmt.Printf("%s\n", packageNevra(pack))
+			resources = append(resources, pack.Pulp_href)
+		}
+		return versionHref, resources, nil
+	}
+	for _, id := range args {
+		info, err := pulpAdvisoryInfo(id, versionHref)
+		if err != nil {
+			return "", nil, err
+		}
+		if info.Count == 0 {
+			return "", nil, fmt.Errorf("advisory %s not found in repository %s", id, from.Name)
+		}
+		fmt.Printf("%s\t%s\n", info.Results[0].Id, info.Results[0].Title)
+		resources = append(resources, info.Results[0].Pulp_href)
+	}
+	return versionHref, resources, nil
+}
+
+func pulpCopy(from string, version int, to string, args []string, advisories bool, deps bool) (string, error) {
+
+	fromInfo, err := pulpRepositoryInfo(from)
+	if err != nil {
+		return "", err
+	}
+	if fromInfo.Count == 0 {
+		return "", fmt.Errorf("repository %s does not exist", from)
+	}
+	toInfo, err := pulpRepositoryInfo(to)
+	if err != nil {
+		return "", err
+	}
+	if toInfo.Count == 0 {
+		return "", fmt.Errorf("repository %s does not exist", to)
+	}
+	versionHref, resources, err := pulpCopyContents(fromInfo.Results[0], version, args, advisories)
+	if err != nil {
+		return "", err
+	}
+	// Pulp copies the packages of an advisory along with it, and with
+	// dependency solving also the dependencies of the packages.
+	content := CopySet{
+		Config: []CopyConfig{
+			{
+				Source_repo_version: versionHref,
+				Dest_repo:           toInfo.Results[0].Pulp_href,
+				Content:             resources,
+			},
+		},
+		Dependency_solving: deps,
+	}
+	body, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	data := bytes.NewReader(body)
+	req, err := http.NewRequest("POST", apiEnd+"/rpm/copy/", data)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	result, status, err := pulpExec(req)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusAccepted {
+		return "", fmt.Errorf("HTTP response: %d, body: %s", status, string(result))
+	}
+	task := Task{}
+	err = json.Unmarshal(result, &task)
+	if err != nil {
+		return "", err
+	}
+	taskResults, err := pulpWaitForTask(task)
+	if err != nil {
+		return "", err
+	}
+	kind := "packages"
+	if advisories {
+		kind = "advisories"
+	}
+	for _, resource := range taskResults.Created_resources {
+		if strings.Contains(resource, "/versions/") {
+			fmt.Printf("Copied %d %s from repository %s to %s.\n", len(resources), kind, from, to)
+			return resource, nil
+		}
+	}
+	// Without a new version nothing changed.
+	return "", nil
+}
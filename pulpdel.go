@@ -1,6 +1,6 @@
 /* Pulp CLI
  *
- * - Version 1.0 - 2021/07/31
+ * - Version 1.23.0 - 2026/10/19
  */
 package main
 
@@ -9,69 +9,137 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 )
 
-func pulpDelPackage(repo, pack string) error {
+func packageNevra(pack PulpContent) string {
 
-	var (
-		pcr = PulpContentResults{
-			Count:    0,
-			Next:     "",
-			Previous: "",
-			Results:  []PulpContent{},
-		}
-		remove []string = make([]string, 1)
-	)
+	return rpmNevra(pack.Name, pack.Epoch, pack.Version, pack.Release, pack.Arch)
+}
 
-	cinfo, err := pulpContentInfo(deconstructPackage(pack))
-	if err != nil {
-		return err
-	}
-	if cinfo.Count == 0 {
-		return fmt.Errorf("contents of %s not found", pack)
+func packagePrefix(pattern string) string {
+
+	// The literal start of a pattern is always a prefix of the name of the
+	// packages it matches.
+	end := strings.IndexAny(pattern, "*?[\\-.:")
+	if end < 0 {
+		return pattern
 	}
+	return pattern[:end]
+}
+
+func pulpResolvePackages(repo string, args []string) ([]PulpContent, error) {
+
 	rinfo, err := pulpRepositoryInfo(repo)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if rinfo.Count == 0 {
-		return fmt.Errorf("repository %s not found", repo)
+		return nil, fmt.Errorf("repository %s not found", repo)
 	}
-	// Check if package is actually in repo
-	requestString := apiEnd + "/content/rpm/packages/"
-	requestString += "?repository_version=" + rinfo.Results[0].Latest_version_href
-	requestString += "&pkgId=" + cinfo.Results[0].PkgId
-	req, err := http.NewRequest("GET", requestString, nil)
+	return pulpResolveVersionPackages(repo, rinfo.Results[0].Latest_version_href, args)
+}
+
+func pulpResolveVersionPackages(repo string, version string, args []string) ([]PulpContent, error) {
+
+	var (
+		matches []PulpContent
+		seen    = make(map[string]bool)
+	)
+
+	for _, arg := range args {
+		var found []PulpContent
+
+		_, err := os.Stat(arg)
+		if err == nil && filepath.Ext(arg) == ".rpm" {
+			// The header of a local package tells exactly what to remove.
+			details, err := localPackageDetails(arg)
+			if err != nil {
+				return nil, err
+			}
+			packs, err := pulpVersionPackages(version, "&name="+url.QueryEscape(details.Name))
+			if err != nil {
+				return nil, err
+			}
+			for _, pack := range packs {
+				epoch := pack.Epoch
+				if epoch == "" {
+					epoch = "0"
+				}
+				if epoch == details.Epoch && pack.Version == details.Version && pack.Release == details.Release && pack.Arch == details.Arch {
+					found = append(found, pack)
+				}
+			}
+		} else {
+			query := ""
+			prefix := packagePrefix(arg)
+			if prefix != "" {
+				query = "&name__startswith=" + url.QueryEscape(prefix)
+			}
+			packs, err := pulpVersionPackages(version, query)
+			if err != nil {
+				return nil, err
+			}
+			for _, pack := range packs {
+				if matchPackage(arg, pack) {
+					found = append(found, pack)
+				}
+			}
+		}
+		if len(found) == 0 {
+			return nil, fmt.Errorf("no package in repository %s matches %s", repo, arg)
+		}
+		for _, pack := range found {
+			if !seen[pack.Pulp_href] {
+				seen[pack.Pulp_href] = true
+				matches = append(matches, pack)
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return packageNevra(matches[i]) < packageNevra(matches[j])
+	})
+	return matches, nil
+}
+
+func pulpDelPublication(pub PulpPublish) error {
+
+	req, err := http.NewRequest("DELETE", apiSrv+pub.Pulp_href, nil)
 	if err != nil {
 		return err
 	}
-	body, status, err := pulpExec(req)
+	_, status, err := pulpExec(req)
 	if err != nil {
 		return err
 	}
-	if status != http.StatusOK {
+	if status != http.StatusNoContent {
 		return fmt.Errorf("HTTP response: %d", status)
 	}
-	err = json.Unmarshal(body, &pcr)
+	return nil
+}
+
+func pulpRemoveContentsFromRepo(repo string, resources []string) error {
+
+	r, err := pulpRepositoryInfo(repo)
 	if err != nil {
 		return err
 	}
-	if pcr.Count == 0 {
-		return fmt.Errorf("content of package %s is not linked with repo %s", pack, repo)
+	if r.Count == 0 {
+		return fmt.Errorf("repository %s does not exist", repo)
 	}
-	// Remove content
-	remove[0] = cinfo.Results[0].Pulp_href
 	content := RemoveContentUnits{
-		Remove_content_units: remove,
+		Remove_content_units: resources,
 	}
-	body, err = json.Marshal(content)
+	body, err := json.Marshal(content)
 	if err != nil {
 		return err
 	}
 	data := bytes.NewReader(body)
-	requestString = apiSrv + rinfo.Results[0].Pulp_href
-	requestString += "modify/"
-	req, err = http.NewRequest("POST", requestString, data)
+	req, err := http.NewRequest("POST", apiSrv+r.Results[0].Pulp_href+"modify/", data)
 	if err != nil {
 		return err
 	}
@@ -97,9 +165,35 @@ func pulpDelPackage(repo, pack string) error {
 	return nil
 }
 
-func pulpDelPublication(pub PulpPublish) error {
+func pulpDelRepoVersion(version string) error {
 
-	req, err := http.NewRequest("DELETE", apiSrv+pub.Pulp_href, nil)
+	req, err := http.NewRequest("DELETE", apiSrv+version, nil)
+	if err != nil {
+		return err
+	}
+	result, status, err := pulpExec(req)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusAccepted {
+		return fmt.Errorf("HTTP response: %d, body: %s", status, string(result))
+	}
+	task := Task{}
+	err = json.Unmarshal(result, &task)
+	if err != nil {
+		return err
+	}
+	_, err = pulpWaitForTask(task)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Repository version removed.\n")
+	return nil
+}
+
+func pulpDelArtifact(artifact string) error {
+
+	req, err := http.NewRequest("DELETE", apiSrv+artifact, nil)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,274 @@
+/* Pulp CLI
+ *
+ * - Version 1.24.0 - 2026/10/19
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+)
+
+func packageEvr(pack PulpContent) string {
+
+	if pack.Epoch != "" && pack.Epoch != "0" {
+		return pack.Epoch + ":" + pack.Version + "-" + pack.Release
+	}
+	return pack.Version + "-" + pack.Release
+}
+
+func packageNewest(packs []PulpContent) int {
+
+	newest := 0
+	for i := range packs {
+		if rpmEvrCmp(packs[i].Epoch, packs[i].Version, packs[i].Release, packs[newest].Epoch, packs[newest].Version, packs[newest].Release) > 0 {
+			newest = i
+		}
+	}
+	return newest
+}
+
+func diffPackages(from []PulpContent, to []PulpContent) []PackageDiff {
+
+	// Packages are compared by name and arch. When a package changed, its
+	// newest versions on both sides make an upgrade or a downgrade, any other
+	// versions count as added or removed.
+	var (
+		changes []PackageDiff
+		keys    []string
+		seen    = make(map[string]bool)
+		gone    = make(map[string][]PulpContent)
+		came    = make(map[string][]PulpContent)
+	)
+
+	for _, pack := range from {
+		seen[packageNevra(pack)] = true
+	}
+	for _, pack := range to {
+		nevra := packageNevra(pack)
+		if seen[nevra] {
+			// In both versions, unchanged.
+			seen[nevra] = false
+			continue
+		}
+		key := pack.Name + "." + pack.Arch
+		came[key] = append(came[key], pack)
+	}
+	for _, pack := range from {
+		if seen[packageNevra(pack)] {
+			key := pack.Name + "." + pack.Arch
+			gone[key] = append(gone[key], pack)
+		}
+	}
+	for key := range gone {
+		keys = append(keys, key)
+	}
+	for key := range came {
+		if _, ok := gone[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		removed := gone[key]
+		added := came[key]
+		if len(removed) > 0 && len(added) > 0 {
+			i := packageNewest(removed)
+			j := packageNewest(added)
+			change := PackageDiff{
+				Change: "upgraded",
+				Name:   added[j].Name,
+				Arch:   added[j].Arch,
+				From:   packageEvr(removed[i]),
+				To:     packageEvr(added[j]),
+			}
+			if rpmEvrCmp(added[j].Epoch, added[j].Version, added[j].Release, removed[i].Epoch, removed[i].Version, removed[i].Release) < 0 {
+				change.Change = "downgraded"
+			}
+			changes = append(changes, change)
+			removed = append(removed[:i:i], removed[i+1:]...)
+			added = append(added[:j:j], added[j+1:]...)
+		}
+		for _, pack := range removed {
+			changes = append(changes, PackageDiff{
+				Change: "removed",
+				Name:   pack.Name,
+				Arch:   pack.Arch,
+				From:   packageEvr(pack),
+				To:     "",
+			})
+		}
+		for _, pack := range added {
+			changes = append(changes, PackageDiff{
+				Change: "added",
+				Name:   pack.Name,
+				Arch:   pack.Arch,
+				From:   "",
+				To:     packageEvr(pack),
+			})
+		}
+	}
+	return changes
+}
+
+func pulpDiffVersions(from string, to string) ([]PackageDiff, error) {
+
+	// An empty version is an empty repository.
+	var (
+		fromPacks, toPacks []PulpContent
+		err                error
+	)
+
+	if from != "" {
+		fromPacks, err = pulpVersionPackages(from, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if to != "" {
+		toPacks, err = pulpVersionPackages(to, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return diffPackages(fromPacks, toPacks), nil
+}
+
+func pulpVersionHref(repo PulpRepository, version int) (string, error) {
+
+	href := repo.Versions_href + strconv.Itoa(version) + "/"
+	req, err := http.NewRequest("GET", apiSrv+href, nil)
+	if err != nil {
+		return "", err
+	}
+	_, status, err := pulpExec(req)
+	if err != nil {
+		return "", err
+	}
+	if status == http.StatusNotFound {
+		return "", fmt.Errorf("repository %s has no version %d", repo.Name, version)
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("HTTP response: %d", status)
+	}
+	return href, nil
+}
+
+func pulpRepoVersionHref(repo string, version int) (string, error) {
+
+	repoInfo, err := pulpRepositoryInfo(repo)
+	if err != nil {
+		return "", err
+	}
+	if repoInfo.Count == 0 {
+		return "", fmt.Errorf("repository %s does not exist", repo)
+	}
+	return pulpVersionHref(repoInfo.Results[0], version)
+}
+
+func pulpPublicationDetails(href string) (PulpPublish, error) {
+
+	pub := PulpPublish{}
+	req, err := http.NewRequest("GET", apiSrv+href, nil)
+	if err != nil {
+		return pub, err
+	}
+	body, status, err := pulpExec(req)
+	if err != nil {
+		return pub, err
+	}
+	if status != http.StatusOK {
+		return pub, fmt.Errorf("HTTP response: %d", status)
+	}
+	err = json.Unmarshal(body, &pub)
+	if err != nil {
+		return pub, err
+	}
+	return pub, nil
+}
+
+func pulpDistributionVersion(distribution string) (string, error) {
+
+	// The version is empty when the distribution serves nothing yet.
+	distInfo, err := pulpDistributionInfo(distribution)
+	if err != nil {
+		return "", err
+	}
+	if distInfo.Count == 0 {
+		return "", fmt.Errorf("distribution %s does not exist", distribution)
+	}
+	dist := distInfo.Results[0]
+	if dist.Publication != "" {
+		pub, err := pulpPublicationDetails(dist.Publication)
+		if err != nil {
+			return "", err
+		}
+		return pub.Repository_version, nil
+	}
+	if dist.Repository != "" {
+		// The distribution serves the latest version of the repository.
+		repo := PulpRepository{}
+		req, err := http.NewRequest("GET", apiSrv+dist.Repository, nil)
+		if err != nil {
+			return "", err
+		}
+		body, status, err := pulpExec(req)
+		if err != nil {
+			return "", err
+		}
+		if status != http.StatusOK {
+			return "", fmt.Errorf("HTTP response: %d", status)
+		}
+		err = json.Unmarshal(body, &repo)
+		if err != nil {
+			return "", err
+		}
+		return repo.Latest_version_href, nil
+	}
+	return "", nil
+}
+
+func versionLabel(name string, version string) string {
+
+	if version == "" {
+		return name + " serving nothing"
+	}
+	return name + " version " + path.Base(version)
+}
+
+func printDiff(from string, to string, changes []PackageDiff, asJson bool) error {
+
+	if asJson {
+		result := DiffResult{
+			From:    from,
+			To:      to,
+			Changes: changes,
+		}
+		if result.Changes == nil {
+			result.Changes = []PackageDiff{}
+		}
+		output, err := json.MarshalIndent(result, "", " ")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", output)
+		return nil
+	}
+	if len(changes) == 0 {
+		fmt.Printf("No package differs between %s and %s.\n", from, to)
+		return nil
+	}
+	fmt.Printf("From %s to %s:\n", from, to)
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "CHANGE\tPACKAGE\tFROM\tTO\n")
+	for _, change := range changes {
+		fmt.Fprintf(w, "%s\t%s.%s\t%s\t%s\n", change.Change, change.Name, change.Arch, change.From, change.To)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffPackages(t *testing.T) {
+
+	app10 := testPackage("app", "0", "1.0", "1.el8", "x86_64")
+	app11 := testPackage("app", "0", "1.1", "1.el8", "x86_64")
+	app12 := testPackage("app", "0", "1.2", "1.el8", "x86_64")
+	appNoarch := testPackage("app", "0", "1.0", "1.el8", "noarch")
+	lib20 := testPackage("lib", "0", "2.0", "1.el8", "noarch")
+	lib10e1 := testPackage("lib", "1", "1.0", "1.el8", "noarch")
+	tool := testPackage("tool", "0", "3.0", "1.el8", "x86_64")
+	tests := []struct {
+		name string
+		from []PulpContent
+		to   []PulpContent
+		want []PackageDiff
+	}{
+		{"unchanged", []PulpContent{app10, tool}, []PulpContent{tool, app10}, nil},
+		{"added", []PulpContent{app10}, []PulpContent{app10, tool}, []PackageDiff{
+			{"added", "tool", "x86_64", "", "3.0-1.el8"},
+		}},
+		{"removed", []PulpContent{app10, tool}, []PulpContent{app10}, []PackageDiff{
+			{"removed", "tool", "x86_64", "3.0-1.el8", ""},
+		}},
+		{"from nothing", nil, []PulpContent{tool, app10}, []PackageDiff{
+			{"added", "app", "x86_64", "", "1.0-1.el8"},
+			{"added", "tool", "x86_64", "", "3.0-1.el8"},
+		}},
+		{"upgraded", []PulpContent{app10, tool}, []PulpContent{app11, tool}, []PackageDiff{
+			{"upgraded", "app", "x86_64", "1.0-1.el8", "1.1-1.el8"},
+		}},
+		{"downgraded", []PulpContent{app11}, []PulpContent{app10}, []PackageDiff{
+			{"downgraded", "app", "x86_64", "1.1-1.el8", "1.0-1.el8"},
+		}},
+		{"epoch wins", []PulpContent{lib20}, []PulpContent{lib10e1}, []PackageDiff{
+			{"upgraded", "lib", "noarch", "2.0-1.el8", "1:1.0-1.el8"},
+		}},
+		{"other arch", []PulpContent{app10}, []PulpContent{appNoarch}, []PackageDiff{
+			{"added", "app", "noarch", "", "1.0-1.el8"},
+			{"removed", "app", "x86_64", "1.0-1.el8", ""},
+		}},
+		{"kept version", []PulpContent{app10, app11}, []PulpContent{app11, app12}, []PackageDiff{
+			{"upgraded", "app", "x86_64", "1.0-1.el8", "1.2-1.el8"},
+		}},
+		{"newest upgraded", []PulpContent{app10, app11}, []PulpContent{app12}, []PackageDiff{
+			{"upgraded", "app", "x86_64", "1.1-1.el8", "1.2-1.el8"},
+			{"removed", "app", "x86_64", "1.0-1.el8", ""},
+		}},
+		{"newest downgraded", []PulpContent{app12}, []PulpContent{app11, app10}, []PackageDiff{
+			{"downgraded", "app", "x86_64", "1.2-1.el8", "1.1-1.el8"},
+			{"added", "app", "x86_64", "", "1.0-1.el8"},
+		}},
+	}
+	for _, test := range tests {
+		got := diffPackages(test.from, test.to)
+		if fmt.Sprint(got) != fmt.Sprint(test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
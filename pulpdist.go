@@ -1,18 +1,100 @@
 /* Pulp CLI
  *
- * - Version 1.0 - 2021/07/31
+ * - Version 1.22.0 - 2026/10/19
  */
 package main
 
 import (
 	"bytes"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"net/http"
 )
 
+func addPublishFlags(fs *flag.FlagSet) PublishFlags {
+
+	return PublishFlags{
+		Checksum:         fs.String("checksum", "", "The checksum type of both the metadata and the packages, e.g. sha1 or sha256."),
+		MetadataChecksum: fs.String("metadata-checksum", "", "The checksum type of the repository metadata."),
+		PackageChecksum:  fs.String("package-checksum", "", "The checksum type of the packages in the metadata."),
+		Gpgcheck:         fs.Int("gpgcheck", 0, "Let clients check the package signatures, 0 or 1."),
+		RepoGpgcheck:     fs.Int("repo-gpgcheck", 0, "Let clients check the repository metadata signature, 0 or 1."),
+		Sqlite:           fs.Bool("sqlite", false, "Also generate sqlite metadata."),
+	}
+}
+
+func publishOverrides(fs *flag.FlagSet, pf PublishFlags, settings PublishSet) (PublishSet, error) {
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "checksum":
+			settings.Metadata_checksum_type = *pf.Checksum
+			settings.Package_checksum_type = *pf.Checksum
+		case "metadata-checksum":
+			settings.Metadata_checksum_type = *pf.MetadataChecksum
+		case "package-checksum":
+			settings.Package_checksum_type = *pf.PackageChecksum
+		case "gpgcheck":
+			settings.Gpgcheck = pf.Gpgcheck
+		case "repo-gpgcheck":
+			settings.Repo_gpgcheck = pf.RepoGpgcheck
+		case "sqlite":
+			settings.Sqlite_metadata = pf.Sqlite
+		}
+	})
+	return settings, checkPublishSettings(settings)
+}
+
+func checkPublishSettings(settings PublishSet) error {
+
+	for _, checksum := range []string{settings.Metadata_checksum_type, settings.Package_checksum_type} {
+		switch checksum {
+		case "", "md5", "sha1", "sha224", "sha256", "sha384", "sha512":
+		default:
+			return fmt.Errorf("%s is not a checksum type", checksum)
+		}
+	}
+	for _, check := range []*int{settings.Gpgcheck, settings.Repo_gpgcheck} {
+		if check != nil && *check != 0 && *check != 1 {
+			return fmt.Errorf("gpgcheck and repo_gpgcheck are either 0 or 1")
+		}
+	}
+	return nil
+}
+
+func publishMatches(pub PulpPublish, settings PublishSet) bool {
+
+	// Settings that are not given match anything.
+	if settings.Metadata_checksum_type != "" && settings.Metadata_checksum_type != pub.Metadata_checksum_type {
+		return false
+	}
+	if settings.Package_checksum_type != "" && settings.Package_checksum_type != pub.Package_checksum_type {
+		return false
+	}
+	if settings.Gpgcheck != nil && *settings.Gpgcheck != pub.Gpgcheck {
+		return false
+	}
+	if settings.Repo_gpgcheck != nil && *settings.Repo_gpgcheck != pub.Repo_gpgcheck {
+		return false
+	}
+	if settings.Sqlite_metadata != nil && *settings.Sqlite_metadata != pub.Sqlite_metadata {
+		return false
+	}
+	return true
+}
+
 func pulpPublishPackage(repo string) ([]string, error) {
 
+	settings, err := publishSettings(repo)
+	if err != nil {
+		return nil, err
+	}
+	return pulpPublishRepo(repo, settings)
+}
+
+func pulpPublishRepo(repo string, settings PublishSet) ([]string, error) {
+
 	// Get info on the state of the repository
 	repoinfo, err := pulpRepositoryInfo(repo)
 	if err != nil {
@@ -21,19 +103,18 @@ func pulpPublishPackage(repo string) ([]string, error) {
 	if repoinfo.Count == 0 {
 		return nil, fmt.Errorf("repository %s does not exist", repo)
 	}
-	pubinfo, err := pulpPublishAll()
+	publications, err := pulpPublicationList(repo)
 	if err != nil {
 		return nil, err
 	}
-	for _, pub := range pubinfo.Results {
-		if pub.Repository_version == repoinfo.Results[0].Latest_version_href {
+	for _, pub := range publications {
+		if pub.Repository_version == repoinfo.Results[0].Latest_version_href && publishMatches(pub, settings) {
 			return nil, fmt.Errorf("publication for repository %s already exists", repo)
 		}
 	}
 	// Create new publication
-	content := RepoSet{
-		Repository_version: repoinfo.Results[0].Latest_version_href,
-	}
+	content := settings
+	content.Repository_version = repoinfo.Results[0].Latest_version_href
 	body, err := json.Marshal(content)
 	if err != nil {
 		return nil, err
@@ -85,6 +166,7 @@ func pulpDistributePackage(repo string, publication []string) error {
 		content := DistroSet{
 			Base_path:     info.Distribution + "/" + info.Release + "/" + info.Architecture + "/" + info.Name + "/" + apiEnv[0],
 			Content_guard: "",
+			Pulp_labels:   nil,
 			Name:          repo + "-" + apiEnv[0],
 			Publication:   publication[0],
 		}
@@ -118,51 +200,52 @@ func pulpDistributePackage(repo string, publication []string) error {
 		fmt.Printf("Default repository distribution updated.\n")
 	} else {
 		// New distribution. Create all the distributions.
-		var (
-			content      DistroSet
-			body, result []byte
-			data         *bytes.Reader
-			decoder      *json.Decoder
-			status       int
-			task         Task
-		)
-
 		for _, value := range apiEnv {
-			content = DistroSet{
-				Base_path:     info.Distribution + "/" + info.Release + "/" + info.Architecture + "/" + info.Name + "/" + value,
-				Content_guard: "",
-				Name:          repo + "-" + value,
-				Publication:   publication[0],
-			}
-			body, err = json.Marshal(content)
+			err = pulpCreateDistribution(repo, info, value, publication[0])
 			if err != nil {
 				return err
 			}
-			data = bytes.NewReader(body)
-			req, err = http.NewRequest("POST", apiEnd+"/distributions/rpm/rpm/", data)
-			if err != nil {
-				return err
-			}
-			req.Header.Set("Content-Type", "application/json")
-			result, status, err = pulpExec(req)
-			if err != nil {
-				return err
-			}
-			if status != http.StatusAccepted {
-				return fmt.Errorf("HTTP response: %d, body: %s", status, string(result))
-			}
-			decoder = json.NewDecoder(bytes.NewReader(result))
-			task = Task{}
-			err = decoder.Decode(&task)
-			if err != nil {
-				return err
-			}
-			_, err = pulpWaitForTask(task)
-			if err != nil {
-				return err
-			}
-			fmt.Printf("%s repository distribution created.\n", value)
 		}
 	}
 	return nil
 }
+
+func pulpCreateDistribution(repo string, info RepoDetails, env string, publication string) error {
+
+	content := DistroSet{
+		Base_path:     info.Distribution + "/" + info.Release + "/" + info.Architecture + "/" + info.Name + "/" + env,
+		Content_guard: "",
+		Pulp_labels:   nil,
+		Name:          repo + "-" + env,
+		Publication:   publication,
+	}
+	body, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	data := bytes.NewReader(body)
+	req, err := http.NewRequest("POST", apiEnd+"/distributions/rpm/rpm/", data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	result, status, err := pulpExec(req)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusAccepted {
+		return fmt.Errorf("HTTP response: %d, body: %s", status, string(result))
+	}
+	decoder := json.NewDecoder(bytes.NewReader(result))
+	task := Task{}
+	err = decoder.Decode(&task)
+	if err != nil {
+		return err
+	}
+	_, err = pulpWaitForTask(task)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s repository distribution created.\n", env)
+	return nil
+}
@@ -1,6 +1,6 @@
 /* Pulp CLI
  *
- * - Version 1.1.3 - 2021/09/21
+ * - Version 1.7.0 - 2026/10/19
  */
 package main
 
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"path"
 	"time"
 )
 
@@ -39,6 +40,8 @@ func pulpWaitForTask(task Task) (TaskQuery, error) {
 	if err != nil {
 		return taskQuery, err
 	}
+	progress := newProgress("Task "+path.Base(task.Task), 0, 0)
+	defer progressDone(progress)
 	for status == http.StatusOK && (taskQuery.State == "running" || taskQuery.State == "waiting") {
 		result, status, err = pulpExec(req)
 		if err != nil {
@@ -52,9 +55,19 @@ func pulpWaitForTask(task Task) (TaskQuery, error) {
 		if err != nil {
 			return taskQuery, err
 		}
-		fmt.Printf("Waiting for task %s to finish...\n", taskQuery.Pulp_href)
-		time.Sleep(2 * time.Second)
+		if taskQuery.State == "running" || taskQuery.State == "waiting" {
+			message := taskProgressMessage(taskQuery.Progress_reports)
+			if message == "" {
+				message = taskQuery.State
+			}
+			progressMessage(progress, message)
+			time.Sleep(2 * time.Second)
+		}
 	}
+	progressLock.Lock()
+	progress.message = taskQuery.State
+	progressLock.Unlock()
+	progressDone(progress)
 	if taskQuery.State != "completed" {
 		if taskQuery.Error.Description != "" {
 			return taskQuery, fmt.Errorf("%s", taskQuery.Error.Description)
@@ -116,3 +129,38 @@ func pulpOrphanClean() ([]ProgressReport, error) {
 	}
 	return taskResults.Progress_reports, nil
 }
+
+func pulpOrphanCleanContent(hrefs []string) error {
+
+	// Pulp can't delete content units, but removes them together with
+	// their artifacts when they are orphans. Content units still part of
+	// a repository version are left alone.
+	content := OrphanCleanupSet{
+		Content_hrefs:          hrefs,
+		Orphan_protection_time: 0,
+	}
+	body, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", apiEnd+"/orphans/cleanup/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	result, status, err := pulpExec(req)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusAccepted {
+		return fmt.Errorf("HTTP response: %d, body: %s", status, string(result))
+	}
+	decoder := json.NewDecoder(bytes.NewReader(result))
+	task := Task{}
+	err = decoder.Decode(&task)
+	if err != nil {
+		return err
+	}
+	_, err = pulpWaitForTask(task)
+	return err
+}
@@ -1,6 +1,6 @@
 /* Pulp CLI
  *
- * - Version 1.1.0 - 2021/08/19
+ * - Version 1.22.0 - 2026/10/19
  */
 package main
 
@@ -8,31 +8,50 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 )
 
-func pulpRepositoryAll() (PulpRepositoryResults, error) {
+func pulpRepositoryAll(selector string) (PulpRepositoryResults, error) {
 
-	var r = PulpRepositoryResults{
-		Count:    0,
-		Next:     "",
-		Previous: "",
-		Results:  []PulpRepository{},
-	}
+	var (
+		r = PulpRepositoryResults{
+			Count:    0,
+			Next:     "",
+			Previous: "",
+			Results:  []PulpRepository{},
+		}
+		page PulpRepositoryResults
+	)
 
-	req, err := http.NewRequest("GET", apiEnd+"/repositories/rpm/rpm/", nil)
-	if err != nil {
-		return r, err
-	}
-	body, status, err := pulpExec(req)
-	if err != nil {
-		return r, err
-	}
-	if status != http.StatusOK {
-		return r, fmt.Errorf("HTTP response: %d", status)
-	}
-	err = json.Unmarshal(body, &r)
-	if err != nil {
-		return r, err
+	requestString := apiEnd + "/repositories/rpm/rpm/"
+	if selector != "" {
+		requestString += "?pulp_label_select=" + url.QueryEscape(selector)
+	}
+	for requestString != "" {
+		req, err := http.NewRequest("GET", requestString, nil)
+		if err != nil {
+			return r, err
+		}
+		body, status, err := pulpExec(req)
+		if err != nil {
+			return r, err
+		}
+		if status != http.StatusOK {
+			return r, fmt.Errorf("HTTP response: %d", status)
+		}
+		page = PulpRepositoryResults{
+			Count:    0,
+			Next:     "",
+			Previous: "",
+			Results:  []PulpRepository{},
+		}
+		err = json.Unmarshal(body, &page)
+		if err != nil {
+			return r, err
+		}
+		r.Count = page.Count
+		r.Results = append(r.Results, page.Results...)
+		requestString = page.Next
 	}
 	return r, nil
 }
@@ -118,7 +137,7 @@ func pulpDistributionInfo(distribution string) (PulpDistributionResults, error)
 	return r, nil
 }
 
-func pulpPackageInfo(pack string) (PulpCreateResults, error) {
+func pulpPackageInfo(sha256 string) (PulpCreateResults, error) {
 
 	var r = PulpCreateResults{
 		Count:    0,
@@ -127,10 +146,6 @@ func pulpPackageInfo(pack string) (PulpCreateResults, error) {
 		Results:  []PulpCreate{},
 	}
 
-	sha256, err := calcSHA256(pack)
-	if err != nil {
-		return r, err
-	}
 	req, err := http.NewRequest("GET", apiEnd+"/artifacts/?sha256="+sha256, nil)
 	if err != nil {
 		return r, err
@@ -159,7 +174,10 @@ func pulpContentInfo(pack PackageDetails) (PulpContentResults, error) {
 	}
 
 	requestString := apiEnd + "/content/rpm/packages/"
-	requestString += "?name=" + pack.Name
+	requestString += "?name=" + url.QueryEscape(pack.Name)
+	if pack.Epoch != "" {
+		requestString += "&epoch=" + pack.Epoch
+	}
 	requestString += "&version=" + pack.Version
 	requestString += "&release=" + pack.Release
 	requestString += "&arch=" + pack.Arch
@@ -181,6 +199,39 @@ func pulpContentInfo(pack PackageDetails) (PulpContentResults, error) {
 	return r, nil
 }
 
+func pulpPackageContent(sha256 string, version string) (PulpContentResults, error) {
+
+	var r = PulpContentResults{
+		Count:    0,
+		Next:     "",
+		Previous: "",
+		Results:  []PulpContent{},
+	}
+
+	// Pulp uses the sha256 checksum of the package file as its pkgId. When
+	// a repository version is given, only its content is searched.
+	requestString := apiEnd + "/content/rpm/packages/?pkgId=" + sha256
+	if version != "" {
+		requestString += "&repository_version=" + url.QueryEscape(version)
+	}
+	req, err := http.NewRequest("GET", requestString, nil)
+	if err != nil {
+		return r, err
+	}
+	body, status, err := pulpExec(req)
+	if err != nil {
+		return r, err
+	}
+	if status != http.StatusOK {
+		return r, fmt.Errorf("HTTP response: %d, expected: %d", status, http.StatusOK)
+	}
+	err = json.Unmarshal(body, &r)
+	if err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
 func pulpArtifactInfo(artifact_href string) (PulpCreate, error) {
 
 	var (
@@ -216,17 +267,38 @@ func pulpArtifactInfo(artifact_href string) (PulpCreate, error) {
 	return pc, nil
 }
 
-/*
-func pulpRemoteInfo(repo string) (PulpRepositoryResults, error) {
+func pulpContentDetails(content_href string) (PulpContent, error) {
 
-	var r = PulpRepositoryResults{
+	var pc PulpContent
+
+	req, err := http.NewRequest("GET", apiSrv+content_href, nil)
+	if err != nil {
+		return pc, err
+	}
+	result, status, err := pulpExec(req)
+	if err != nil {
+		return pc, err
+	}
+	if status != http.StatusOK {
+		return pc, fmt.Errorf("HTTP response: %d, expected: %d", status, http.StatusOK)
+	}
+	err = json.Unmarshal(result, &pc)
+	if err != nil {
+		return pc, err
+	}
+	return pc, nil
+}
+
+func pulpRemoteInfo(remote string) (PulpRemoteResults, error) {
+
+	var r = PulpRemoteResults{
 		Count:    0,
 		Next:     "",
 		Previous: "",
-		Results:  []PulpRepository{},
+		Results:  []PulpRemote{},
 	}
 
-	req, err := http.NewRequest("GET", apiEnd+"/remotes/rpm/rpm/?name="+repo, nil)
+	req, err := http.NewRequest("GET", apiEnd+"/remotes/rpm/rpm/?name="+url.QueryEscape(remote), nil)
 	if err != nil {
 		return r, err
 	}
@@ -234,9 +306,6 @@ func pulpRemoteInfo(repo string) (PulpRepositoryResults, error) {
 	if err != nil {
 		return r, err
 	}
-	if status == http.StatusNotFound {
-		return r, fmt.Errorf("remote does not exist. Are you sure this is a synched repository?")
-	}
 	if status != http.StatusOK {
 		return r, fmt.Errorf("HTTP response: %d", status)
 	}
@@ -246,4 +315,3 @@ func pulpRemoteInfo(repo string) (PulpRepositoryResults, error) {
 	}
 	return r, nil
 }
-*/
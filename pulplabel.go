@@ -0,0 +1,125 @@
+/* Pulp CLI
+ *
+ * - Version 1.22.0 - 2026/10/19
+ */
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+func pulpLabelTarget(kind string, name string) (string, PulpLabels, error) {
+
+	switch kind {
+	case "repository":
+		info, err := pulpRepositoryInfo(name)
+		if err != nil {
+			return "", nil, err
+		}
+		if info.Count == 0 {
+			return "", nil, fmt.Errorf("repository %s does not exist", name)
+		}
+		return info.Results[0].Pulp_href, info.Results[0].Pulp_labels, nil
+	case "distribution":
+		info, err := pulpDistributionInfo(name)
+		if err != nil {
+			return "", nil, err
+		}
+		if info.Count == 0 {
+			return "", nil, fmt.Errorf("distribution %s does not exist", name)
+		}
+		return info.Results[0].Pulp_href, info.Results[0].Pulp_labels, nil
+	case "remote":
+		remote, err := pulpRemoteByName(name)
+		if err != nil {
+			return "", nil, err
+		}
+		return remote.Pulp_href, remote.Pulp_labels, nil
+	}
+	return "", nil, fmt.Errorf("%s is neither repository, distribution nor remote", kind)
+}
+
+func parseLabels(args []string) (PulpLabels, error) {
+
+	labels := make(PulpLabels)
+	for _, arg := range args {
+		key, value, found := strings.Cut(arg, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("%s is not a key=value label", arg)
+		}
+		labels[key] = strings.TrimSpace(value)
+	}
+	return labels, nil
+}
+
+func pulpUpdateLabels(kind string, name string, set PulpLabels, unset []string) error {
+
+	// The other labels the object already has are kept.
+	href, labels, err := pulpLabelTarget(kind, name)
+	if err != nil {
+		return err
+	}
+	if labels == nil {
+		labels = make(PulpLabels)
+	}
+	for key, value := range set {
+		labels[key] = value
+	}
+	for _, key := range unset {
+		_, ok := labels[key]
+		if !ok {
+			return fmt.Errorf("%s %s has no label %s", kind, name, key)
+		}
+		delete(labels, key)
+	}
+	content := LabelSet{
+		Pulp_labels: labels,
+	}
+	body, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	data := bytes.NewReader(body)
+	req, err := http.NewRequest("PATCH", apiSrv+href, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	result, status, err := pulpExec(req)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusAccepted {
+		return fmt.Errorf("HTTP response: %d, body: %s", status, string(result))
+	}
+	task := Task{}
+	err = json.Unmarshal(result, &task)
+	if err != nil {
+		return err
+	}
+	_, err = pulpWaitForTask(task)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Labels of %s %s updated.\n", kind, name)
+	return nil
+}
+
+func printLabels(labels PulpLabels) {
+
+	var keys []string
+
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("%s=%s\n", key, labels[key])
+	}
+}
@@ -1,6 +1,6 @@
 /* Pulp CLI
  *
- * - Version 1.1.4 - 2021/11/09
+ * - Version 1.25.0 - 2026/10/19
  */
 package main
 
@@ -8,6 +8,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"path"
+	"sort"
 )
 
 func pulpPublicationList(repo string) ([]PulpPublish, error) {
@@ -26,7 +29,7 @@ func pulpPublicationList(repo string) ([]PulpPublish, error) {
 	}
 	reference := repoInfo.Results[0].Pulp_href
 	url = apiEnd + "/publications/rpm/rpm/"
-	for {
+	for url != "" {
 		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
 			return nil, err
@@ -38,6 +41,12 @@ func pulpPublicationList(repo string) ([]PulpPublish, error) {
 		if status != http.StatusOK {
 			return nil, fmt.Errorf("HTTP response: %d", status)
 		}
+		pubInfo = PulpPublishResults{
+			Count:    0,
+			Next:     "",
+			Previous: "",
+			Results:  []PulpPublish{},
+		}
 		err = json.Unmarshal(body, &pubInfo)
 		if err != nil {
 			return nil, err
@@ -47,11 +56,7 @@ func pulpPublicationList(repo string) ([]PulpPublish, error) {
 				results = append(results, pub)
 			}
 		}
-		if url != pubInfo.Next {
-			url = pubInfo.Next
-		} else {
-			break
-		}
+		url = pubInfo.Next
 	}
 	return results, nil
 }
@@ -72,6 +77,9 @@ func pulpDistributionList(repo string) ([]PulpDistActive, error) {
 		if err != nil {
 			return nil, err
 		}
+		if distInfo.Count == 0 {
+			continue
+		}
 		for _, pub := range publications {
 			if pub.Pulp_href == distInfo.Results[0].Publication {
 				result.Distribution = repo + "-" + env
@@ -82,3 +90,128 @@ func pulpDistributionList(repo string) ([]PulpDistActive, error) {
 	}
 	return resultSet, nil
 }
+
+func pulpPackageList(repo string, kind string) ([]PulpContent, error) {
+
+	repoInfo, err := pulpRepositoryInfo(repo)
+	if err != nil {
+		return nil, err
+	}
+	if repoInfo.Count == 0 {
+		return nil, fmt.Errorf("repository %s does not exist", repo)
+	}
+	filter := PackageFilter{
+		Kind:   kind,
+		Name:   "",
+		Arch:   "",
+		Newest: false,
+	}
+	return pulpFilterPackages(repoInfo.Results[0].Latest_version_href, filter)
+}
+
+func newestPackages(packs []PulpContent) []PulpContent {
+
+	var (
+		newest []PulpContent
+		keys   []string
+		groups = make(map[string][]PulpContent)
+	)
+
+	for _, pack := range packs {
+		key := pack.Name + "." + pack.Arch
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], pack)
+	}
+	for _, key := range keys {
+		group := groups[key]
+		newest = append(newest, group[packageNewest(group)])
+	}
+	return newest
+}
+
+func pulpFilterPackages(version string, filter PackageFilter) ([]PulpContent, error) {
+
+	var results []PulpContent
+
+	query := ""
+	switch filter.Kind {
+	case "bin":
+		query = "&arch__ne=src"
+	case "src":
+		query = "&arch=src"
+	}
+	if filter.Arch != "" {
+		query += "&arch=" + url.QueryEscape(filter.Arch)
+	}
+	if filter.Name != "" {
+		prefix := packagePrefix(filter.Name)
+		if prefix != "" {
+			query += "&name__startswith=" + url.QueryEscape(prefix)
+		}
+	}
+	packs, err := pulpVersionPackages(version, query)
+	if err != nil {
+		return nil, err
+	}
+	for _, pack := range packs {
+		if filter.Name != "" {
+			matched, err := path.Match(filter.Name, pack.Name)
+			if err != nil {
+				return nil, fmt.Errorf("bad name pattern %s", filter.Name)
+			}
+			if !matched {
+				continue
+			}
+		}
+		results = append(results, pack)
+	}
+	if filter.Newest {
+		results = newestPackages(results)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return packageNevra(results[i]) < packageNevra(results[j])
+	})
+	return results, nil
+}
+
+// Files, dependencies and changelogs make up most of a package record and
+// are left out.
+const PACKAGE_FIELDS string = "pulp_href,name,epoch,version,release,arch,pkgId,location_href,size_package,time_build"
+
+func pulpVersionPackages(version string, query string) ([]PulpContent, error) {
+
+	var (
+		contentInfo PulpContentResults
+		results     []PulpContent
+	)
+
+	requestString := apiEnd + "/content/rpm/packages/?repository_version=" + url.QueryEscape(version) + "&fields=" + PACKAGE_FIELDS + query
+	for requestString != "" {
+		req, err := http.NewRequest("GET", requestString, nil)
+		if err != nil {
+			return nil, err
+		}
+		body, status, err := pulpExec(req)
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("HTTP response: %d", status)
+		}
+		contentInfo = PulpContentResults{
+			Count:    0,
+			Next:     "",
+			Previous: "",
+			Results:  []PulpContent{},
+		}
+		err = json.Unmarshal(body, &contentInfo)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, contentInfo.Results...)
+		requestString = contentInfo.Next
+	}
+	return results, nil
+}
@@ -0,0 +1,226 @@
+/* Pulp CLI
+ *
+ * - Version 1.18.0 - 2026/10/19
+ */
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+func prunePackages(packs []PulpContent, keep int) []PulpContent {
+
+	var remove []PulpContent
+
+	groups := make(map[string][]PulpContent)
+	for _, pack := range packs {
+		key := pack.Name + "." + pack.Arch
+		groups[key] = append(groups[key], pack)
+	}
+	for _, group := range groups {
+		if len(group) <= keep {
+			continue
+		}
+		// Newest first, in rpm version order.
+		sort.Slice(group, func(i, j int) bool {
+			return rpmEvrCmp(group[i].Epoch, group[i].Version, group[i].Release, group[j].Epoch, group[j].Version, group[j].Release) > 0
+		})
+		remove = append(remove, group[keep:]...)
+	}
+	sort.Slice(remove, func(i, j int) bool {
+		return packageNevra(remove[i]) < packageNevra(remove[j])
+	})
+	return remove
+}
+
+func pruneRepository(versions []PulpRepoVersion, publications []PulpPublish, distributions []PulpDistribution, repo PulpRepository, keep int, cutoff time.Time) ([]PulpPublish, []PulpRepoVersion) {
+
+	// The latest version and every version a distribution serves are never
+	// removed, with a cutoff only older versions are.
+	var (
+		pubs   []PulpPublish
+		remove []PulpRepoVersion
+		served = make(map[string]bool)
+		kept   = make(map[string]bool)
+		newest = make(map[string]PulpPublish)
+	)
+
+	for _, dist := range distributions {
+		if dist.Publication != "" {
+			served[dist.Publication] = true
+		}
+	}
+	for _, pub := range publications {
+		if served[pub.Pulp_href] {
+			kept[pub.Repository_version] = true
+		}
+		if pub.Pulp_created > newest[pub.Repository_version].Pulp_created {
+			newest[pub.Repository_version] = pub
+		}
+	}
+	kept[repo.Latest_version_href] = true
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Number > versions[j].Number
+	})
+	for i, version := range versions {
+		// Version 0 is the empty repository, there is nothing to reclaim.
+		if i < keep || version.Number == 0 || kept[version.Pulp_href] {
+			kept[version.Pulp_href] = true
+			continue
+		}
+		if !cutoff.IsZero() {
+			created, err := time.Parse(time.RFC3339Nano, version.Pulp_created)
+			if err != nil || created.After(cutoff) {
+				kept[version.Pulp_href] = true
+				continue
+			}
+		}
+		remove = append(remove, version)
+	}
+	// Only the newest publication of a kept version is worth keeping.
+	for _, pub := range publications {
+		if served[pub.Pulp_href] {
+			continue
+		}
+		if kept[pub.Repository_version] && newest[pub.Repository_version].Pulp_href == pub.Pulp_href {
+			continue
+		}
+		pubs = append(pubs, pub)
+	}
+	sort.Slice(pubs, func(i, j int) bool {
+		return pubs[i].Pulp_created < pubs[j].Pulp_created
+	})
+	return pubs, remove
+}
+
+func pulpRepoVersionList(repo string) ([]PulpRepoVersion, error) {
+
+	var (
+		verInfo PulpRepoVersionResults
+		results []PulpRepoVersion
+	)
+
+	repoInfo, err := pulpRepositoryInfo(repo)
+	if err != nil {
+		return nil, err
+	}
+	if repoInfo.Count == 0 {
+		return nil, fmt.Errorf("repository %s does not exist", repo)
+	}
+	requestString := apiSrv + repoInfo.Results[0].Versions_href
+	for requestString != "" {
+		req, err := http.NewRequest("GET", requestString, nil)
+		if err != nil {
+			return nil, err
+		}
+		body, status, err := pulpExec(req)
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("HTTP response: %d", status)
+		}
+		verInfo = PulpRepoVersionResults{
+			Count:    0,
+			Next:     "",
+			Previous: "",
+			Results:  []PulpRepoVersion{},
+		}
+		err = json.Unmarshal(body, &verInfo)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, verInfo.Results...)
+		requestString = verInfo.Next
+	}
+	return results, nil
+}
+
+func pulpDistributionAll() ([]PulpDistribution, error) {
+
+	var (
+		distInfo PulpDistributionResults
+		results  []PulpDistribution
+	)
+
+	requestString := apiEnd + "/distributions/rpm/rpm/"
+	for requestString != "" {
+		req, err := http.NewRequest("GET", requestString, nil)
+		if err != nil {
+			return nil, err
+		}
+		body, status, err := pulpExec(req)
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("HTTP response: %d", status)
+		}
+		distInfo = PulpDistributionResults{
+			Count:    0,
+			Next:     "",
+			Previous: "",
+			Results:  []PulpDistribution{},
+		}
+		err = json.Unmarshal(body, &distInfo)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, distInfo.Results...)
+		requestString = distInfo.Next
+	}
+	return results, nil
+}
+
+func pulpPatchRepository(repo string, content interface{}) error {
+
+	repoInfo, err := pulpRepositoryInfo(repo)
+	if err != nil {
+		return err
+	}
+	if repoInfo.Count == 0 {
+		return fmt.Errorf("repository %s does not exist", repo)
+	}
+	body, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	data := bytes.NewReader(body)
+	req, err := http.NewRequest("PATCH", apiSrv+repoInfo.Results[0].Pulp_href, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	result, status, err := pulpExec(req)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusAccepted {
+		return fmt.Errorf("HTTP response: %d, body: %s", status, string(result))
+	}
+	task := Task{}
+	err = json.Unmarshal(result, &task)
+	if err != nil {
+		return err
+	}
+	_, err = pulpWaitForTask(task)
+	return err
+}
+
+func pulpSetRetention(repo string, keep int) error {
+
+	content := RepoRetainSet{
+		Retain_package_versions: keep,
+	}
+	err := pulpPatchRepository(repo, content)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Repository %s retains %d versions of each package.\n", repo, keep)
+	return nil
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func testPackage(name, epoch, version, release, arch string) PulpContent {
+
+	return PulpContent{
+		Pulp_href: "/pulp/api/v3/content/rpm/packages/" + name + "-" + epoch + ":" + version + "-" + release + "." + arch + "/",
+		Name:      name,
+		Epoch:     epoch,
+		Version:   version,
+		Release:   release,
+		Arch:      arch,
+	}
+}
+
+func nevras(packs []PulpContent) []string {
+
+	var result []string
+
+	for _, pack := range packs {
+		result = append(result, packageNevra(pack))
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestPrunePackages(t *testing.T) {
+
+	packs := []PulpContent{
+		testPackage("app", "0", "1.9", "1.el8", "x86_64"),
+		testPackage("app", "0", "1.10", "1.el8", "x86_64"),
+		testPackage("app", "0", "1.10", "2.el8", "x86_64"),
+		testPackage("app", "0", "1.8", "1.el8", "x86_64"),
+		// Each arch is pruned on its own.
+		testPackage("app", "0", "1.0", "1.el8", "src"),
+		testPackage("app", "0", "1.1", "1.el8", "src"),
+		// The epoch wins over the version.
+		testPackage("lib", "1", "1.0", "1.el8", "noarch"),
+		testPackage("lib", "0", "9.0", "1.el8", "noarch"),
+		testPackage("lib", "0", "8.0", "1.el8", "noarch"),
+		testPackage("tool", "0", "1.0", "1.el8", "x86_64"),
+	}
+	tests := []struct {
+		keep   int
+		remove []PulpContent
+	}{
+		{1, []PulpContent{
+			testPackage("app", "0", "1.9", "1.el8", "x86_64"),
+			testPackage("app", "0", "1.10", "1.el8", "x86_64"),
+			testPackage("app", "0", "1.8", "1.el8", "x86_64"),
+			testPackage("app", "0", "1.0", "1.el8", "src"),
+			testPackage("lib", "0", "9.0", "1.el8", "noarch"),
+			testPackage("lib", "0", "8.0", "1.el8", "noarch"),
+		}},
+		{2, []PulpContent{
+			testPackage("app", "0", "1.9", "1.el8", "x86_64"),
+			testPackage("app", "0", "1.8", "1.el8", "x86_64"),
+			testPackage("lib", "0", "8.0", "1.el8", "noarch"),
+		}},
+		{3, []PulpContent{
+			testPackage("app", "0", "1.8", "1.el8", "x86_64"),
+		}},
+		{4, nil},
+	}
+	for _, test := range tests {
+		input := append([]PulpContent{}, packs...)
+		got := nevras(prunePackages(input, test.keep))
+		want := nevras(test.remove)
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("keep %d: removes %v, want %v", test.keep, got, want)
+		}
+	}
+}
+
+const testRepoHref = "/pulp/api/v3/repositories/rpm/rpm/1/"
+
+func testVersion(number int, created time.Time) PulpRepoVersion {
+
+	return PulpRepoVersion{
+		Pulp_href:    testRepoHref + "versions/" + strconv.Itoa(number) + "/",
+		Pulp_created: created.Format(time.RFC3339Nano),
+		Number:       number,
+	}
+}
+
+func testPublication(name string, version int, created time.Time) PulpPublish {
+
+	return PulpPublish{
+		Pulp_href:          "/pulp/api/v3/publications/rpm/rpm/" + name + "/",
+		Pulp_created:       created.Format(time.RFC3339Nano),
+		Repository_version: testRepoHref + "versions/" + strconv.Itoa(version) + "/",
+	}
+}
+
+func versionNumbers(versions []PulpRepoVersion) []int {
+
+	var result []int
+
+	for _, version := range versions {
+		result = append(result, version.Number)
+	}
+	sort.Ints(result)
+	return result
+}
+
+func publicationNames(pubs []PulpPublish) []string {
+
+	var result []string
+
+	for _, pub := range pubs {
+		result = append(result, pub.Pulp_href)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestPruneRepository(t *testing.T) {
+
+	now := time.Now()
+	day := 24 * time.Hour
+	// Version n was created n days after version 0, the latest one now.
+	var versions []PulpRepoVersion
+	for n := 0; n <= 6; n++ {
+		versions = append(versions, testVersion(n, now.Add(time.Duration(n-6)*day)))
+	}
+	publications := []PulpPublish{
+		testPublication("p1", 1, now.Add(-5*day)),
+		testPublication("p2", 2, now.Add(-4*day)),
+		testPublication("p3", 3, now.Add(-3*day)),
+		testPublication("p5a", 5, now.Add(-day)),
+		testPublication("p5b", 5, now.Add(-day+time.Hour)),
+		testPublication("p6a", 6, now.Add(-time.Hour)),
+		testPublication("p6b", 6, now),
+	}
+	// Production still serves version 2, while the older publication of
+	// version 6 is served outside of the environments.
+	distributions := []PulpDistribution{
+		{Name: "myrepo-rh8-x86_64-prd", Publication: publications[1].Pulp_href},
+		{Name: "myrepo-rh8-x86_64-dev", Publication: publications[6].Pulp_href},
+		{Name: "pinned", Publication: publications[5].Pulp_href},
+		{Name: "follows-latest", Repository: testRepoHref},
+	}
+	repo := PulpRepository{
+		Pulp_href:           testRepoHref,
+		Name:                "myrepo-rh8-x86_64",
+		Latest_version_href: testRepoHref + "versions/6/",
+	}
+	tests := []struct {
+		name     string
+		keep     int
+		cutoff   time.Time
+		versions []int
+		pubs     []string
+	}{
+		{"keep newest", 2, time.Time{}, []int{1, 3, 4}, []string{"p1", "p3", "p5a"}},
+		{"keep latest", 1, time.Time{}, []int{1, 3, 4, 5}, []string{"p1", "p3", "p5a", "p5b"}},
+		{"keep all", 7, time.Time{}, nil, []string{"p5a"}},
+		{"older than", 1, now.Add(-3*day - time.Minute), []int{1}, []string{"p1", "p5a"}},
+	}
+	for _, test := range tests {
+		input := append([]PulpRepoVersion{}, versions...)
+		pubs, remove := pruneRepository(input, publications, distributions, repo, test.keep, test.cutoff)
+		if fmt.Sprint(versionNumbers(remove)) != fmt.Sprint(test.versions) {
+			t.Errorf("%s: removes versions %v, want %v", test.name, versionNumbers(remove), test.versions)
+		}
+		var want []string
+		for _, name := range test.pubs {
+			want = append(want, "/pulp/api/v3/publications/rpm/rpm/"+name+"/")
+		}
+		sort.Strings(want)
+		if fmt.Sprint(publicationNames(pubs)) != fmt.Sprint(want) {
+			t.Errorf("%s: removes publications %v, want %v", test.name, publicationNames(pubs), want)
+		}
+		for _, version := range remove {
+			if version.Number == 0 || version.Pulp_href == repo.Latest_version_href || version.Number == 2 {
+				t.Errorf("%s: removes version %d, which must be kept", test.name, version.Number)
+			}
+		}
+		for _, pub := range pubs {
+			for _, dist := range distributions {
+				if dist.Publication == pub.Pulp_href {
+					t.Errorf("%s: removes publication %s served by %s", test.name, pub.Pulp_href, dist.Name)
+				}
+			}
+		}
+	}
+}
+
+func TestPruneRepositoryBadDate(t *testing.T) {
+
+	versions := []PulpRepoVersion{
+		{Pulp_href: testRepoHref + "versions/1/", Pulp_created: "yesterday", Number: 1},
+		{Pulp_href: testRepoHref + "versions/2/", Pulp_created: "today", Number: 2},
+	}
+	repo := PulpRepository{
+		Pulp_href:           testRepoHref,
+		Latest_version_href: testRepoHref + "versions/2/",
+	}
+	// A version of which the age is unknown is never too old.
+	_, remove := pruneRepository(versions, nil, nil, repo, 1, time.Now())
+	if len(remove) != 0 {
+		t.Errorf("removes versions %v of unknown age", versionNumbers(remove))
+	}
+}
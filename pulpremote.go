@@ -0,0 +1,328 @@
+/* Pulp CLI
+ *
+ * - Version 1.22.0 - 2026/10/19
+ */
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+func addRemoteFlags(fs *flag.FlagSet) RemoteFlags {
+
+	return RemoteFlags{
+		Policy:        fs.String("policy", "immediate", "The download policy: immediate, on_demand or streamed."),
+		CaCert:        fs.String("ca-cert", "", "A PEM file with the CA certificate to validate the remote with."),
+		ClientCert:    fs.String("client-cert", "", "A PEM file with the client certificate to authenticate with."),
+		ClientKey:     fs.String("client-key", "", "A PEM file with the private key of the client certificate."),
+		TlsValidation: fs.Bool("tls-validation", true, "Validate the TLS certificate of the remote."),
+		Username:      fs.String("username", "", "The user to authenticate with at the remote."),
+		Password:      fs.String("password", "", "The password to authenticate with at the remote."),
+		Proxy:         fs.String("proxy", "", "The url of a proxy to download through."),
+		ProxyUsername: fs.String("proxy-username", "", "The user to authenticate with at the proxy."),
+		ProxyPassword: fs.String("proxy-password", "", "The password to authenticate with at the proxy."),
+		RateLimit:     fs.Int("rate-limit", 0, "The maximum number of requests per second to the remote."),
+		Concurrency:   fs.Int("concurrency", 0, "The number of concurrent downloads."),
+	}
+}
+
+func readPemFile(file string) (*string, error) {
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pem := string(content)
+	return &pem, nil
+}
+
+func remoteSettings(fs *flag.FlagSet, rf RemoteFlags) (RemoteSet, error) {
+
+	// Flags that are not given are left out, so an update keeps their current
+	// value.
+	var err error
+
+	settings := RemoteSet{
+		Name:                 nil,
+		Url:                  nil,
+		Policy:               nil,
+		Ca_cert:              nil,
+		Client_cert:          nil,
+		Client_key:           nil,
+		Tls_validation:       nil,
+		Username:             nil,
+		Password:             nil,
+		Proxy_url:            nil,
+		Proxy_username:       nil,
+		Proxy_password:       nil,
+		Rate_limit:           nil,
+		Download_concurrency: nil,
+	}
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+	if given["policy"] {
+		switch *rf.Policy {
+		case "immediate", "on_demand", "streamed":
+			settings.Policy = rf.Policy
+		default:
+			return settings, fmt.Errorf("%s is not one of the download policies immediate, on_demand or streamed", *rf.Policy)
+		}
+	}
+	if given["ca-cert"] {
+		settings.Ca_cert, err = readPemFile(*rf.CaCert)
+		if err != nil {
+			return settings, err
+		}
+	}
+	if given["client-cert"] {
+		settings.Client_cert, err = readPemFile(*rf.ClientCert)
+		if err != nil {
+			return settings, err
+		}
+	}
+	if given["client-key"] {
+		settings.Client_key, err = readPemFile(*rf.ClientKey)
+		if err != nil {
+			return settings, err
+		}
+	}
+	if given["tls-validation"] {
+		settings.Tls_validation = rf.TlsValidation
+	}
+	if given["username"] {
+		settings.Username = rf.Username
+	}
+	if given["password"] {
+		settings.Password = rf.Password
+	}
+	if given["proxy"] {
+		settings.Proxy_url = rf.Proxy
+	}
+	if given["proxy-username"] {
+		settings.Proxy_username = rf.ProxyUsername
+	}
+	if given["proxy-password"] {
+		settings.Proxy_password = rf.ProxyPassword
+	}
+	if given["rate-limit"] {
+		if *rf.RateLimit < 1 {
+			return settings, fmt.Errorf("the rate limit must be at least 1")
+		}
+		settings.Rate_limit = rf.RateLimit
+	}
+	if given["concurrency"] {
+		if *rf.Concurrency < 1 {
+			return settings, fmt.Errorf("the download concurrency must be at least 1")
+		}
+		settings.Download_concurrency = rf.Concurrency
+	}
+	return settings, nil
+}
+
+func pulpCreateRemote(settings RemoteSet) (PulpRemote, error) {
+
+	remote := PulpRemote{}
+	body, err := json.Marshal(settings)
+	if err != nil {
+		return remote, err
+	}
+	data := bytes.NewReader(body)
+	req, err := http.NewRequest("POST", apiEnd+"/remotes/rpm/rpm/", data)
+	if err != nil {
+		return remote, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	result, status, err := pulpExec(req)
+	if err != nil {
+		return remote, err
+	}
+	if status != http.StatusCreated {
+		return remote, fmt.Errorf("HTTP response: %d, body: %s", status, string(result))
+	}
+	err = json.Unmarshal(result, &remote)
+	if err != nil {
+		return remote, err
+	}
+	fmt.Printf("Remote %s created.\n", remote.Name)
+	return remote, nil
+}
+
+func pulpRemoteDetails(href string) (PulpRemote, error) {
+
+	remote := PulpRemote{}
+	req, err := http.NewRequest("GET", apiSrv+href, nil)
+	if err != nil {
+		return remote, err
+	}
+	body, status, err := pulpExec(req)
+	if err != nil {
+		return remote, err
+	}
+	if status != http.StatusOK {
+		return remote, fmt.Errorf("HTTP response: %d", status)
+	}
+	err = json.Unmarshal(body, &remote)
+	if err != nil {
+		return remote, err
+	}
+	return remote, nil
+}
+
+func pulpRemoteByName(name string) (PulpRemote, error) {
+
+	info, err := pulpRemoteInfo(name)
+	if err != nil {
+		return PulpRemote{}, err
+	}
+	if info.Count == 0 {
+		return PulpRemote{}, fmt.Errorf("remote %s does not exist", name)
+	}
+	return info.Results[0], nil
+}
+
+func pulpUpdateRemote(name string, settings RemoteSet) error {
+
+	remote, err := pulpRemoteByName(name)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	data := bytes.NewReader(body)
+	req, err := http.NewRequest("PATCH", apiSrv+remote.Pulp_href, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	result, status, err := pulpExec(req)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusAccepted {
+		return fmt.Errorf("HTTP response: %d, body: %s", status, string(result))
+	}
+	task := Task{}
+	err = json.Unmarshal(result, &task)
+	if err != nil {
+		return err
+	}
+	_, err = pulpWaitForTask(task)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Remote %s updated.\n", name)
+	return nil
+}
+
+func pulpRemoteList() ([]PulpRemote, error) {
+
+	var (
+		remInfo PulpRemoteResults
+		results []PulpRemote
+	)
+
+	requestString := apiEnd + "/remotes/rpm/rpm/"
+	for requestString != "" {
+		req, err := http.NewRequest("GET", requestString, nil)
+		if err != nil {
+			return nil, err
+		}
+		body, status, err := pulpExec(req)
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("HTTP response: %d", status)
+		}
+		remInfo = PulpRemoteResults{
+			Count:    0,
+			Next:     "",
+			Previous: "",
+			Results:  []PulpRemote{},
+		}
+		err = json.Unmarshal(body, &remInfo)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, remInfo.Results...)
+		requestString = remInfo.Next
+	}
+	return results, nil
+}
+
+func pulpRemoteUsers(remote PulpRemote) ([]string, error) {
+
+	var users []string
+
+	repos, err := pulpRepositoryAll("")
+	if err != nil {
+		return nil, err
+	}
+	for _, repo := range repos.Results {
+		if repo.Remote == remote.Pulp_href {
+			users = append(users, repo.Name)
+		}
+	}
+	return users, nil
+}
+
+func pulpAttachRemote(repo string, name string) error {
+
+	// Without a remote name the repository is detached from its remote.
+	content := RepoRemoteSet{
+		Remote: nil,
+	}
+	if name != "" {
+		remote, err := pulpRemoteByName(name)
+		if err != nil {
+			return err
+		}
+		content.Remote = &remote.Pulp_href
+	}
+	err := pulpPatchRepository(repo, content)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		fmt.Printf("Repository %s detached from its remote.\n", repo)
+	} else {
+		fmt.Printf("Repository %s attached to remote %s.\n", repo, name)
+	}
+	return nil
+}
+
+func printRemote(remote PulpRemote, users []string) {
+
+	fmt.Printf("Name:          %s\n", remote.Name)
+	fmt.Printf("Url:           %s\n", remote.Url)
+	fmt.Printf("Policy:        %s\n", remote.Policy)
+	fmt.Printf("TLS validated: %t\n", remote.Tls_validation)
+	if remote.Ca_cert != "" {
+		fmt.Printf("CA cert:       set\n")
+	}
+	if remote.Client_cert != "" {
+		fmt.Printf("Client cert:   set\n")
+	}
+	if remote.Proxy_url != "" {
+		fmt.Printf("Proxy:         %s\n", remote.Proxy_url)
+	}
+	if remote.Rate_limit > 0 {
+		fmt.Printf("Rate limit:    %d\n", remote.Rate_limit)
+	}
+	if remote.Download_concurrency > 0 {
+		fmt.Printf("Concurrency:   %d\n", remote.Download_concurrency)
+	}
+	fmt.Printf("Created:       %s\n", remote.Pulp_created)
+	for _, user := range users {
+		fmt.Printf("Repository:    %s\n", user)
+	}
+}
@@ -0,0 +1,197 @@
+/* Pulp CLI
+ *
+ * - Version 1.20.0 - 2026/10/19
+ */
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+func pulpPostRepository(content RepositorySet) error {
+
+	body, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	data := bytes.NewReader(body)
+	req, err := http.NewRequest("POST", apiEnd+"/repositories/rpm/rpm/", data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	result, status, err := pulpExec(req)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusCreated {
+		return fmt.Errorf("HTTP response: %d, body: %s", status, string(result))
+	}
+	return nil
+}
+
+func pulpCreateRepo(repo string, description string, url string) error {
+
+	info, err := deconstructRepository(repo)
+	if err != nil {
+		return err
+	}
+	repoInfo, err := pulpRepositoryInfo(repo)
+	if err != nil {
+		return err
+	}
+	// A retry finishes what a failed run left undone.
+	if repoInfo.Count > 0 {
+		fmt.Printf("Repository %s already exists.\n", repo)
+		if url != "" {
+			fmt.Printf("WARNING the -remote option is ignored, see 'remote attach'.\n")
+		}
+		return pulpCreateDistributions(repo, info)
+	}
+	content := RepositorySet{
+		Name:        repo,
+		Description: description,
+		Remote:      "",
+	}
+	if url != "" {
+		policy := "immediate"
+		settings := RemoteSet{
+			Name:   &repo,
+			Url:    &url,
+			Policy: &policy,
+		}
+		remote, err := pulpCreateRemote(settings)
+		if err != nil {
+			return err
+		}
+		content.Remote = remote.Pulp_href
+	}
+	err = pulpPostRepository(content)
+	if err != nil {
+		// The remote would be in the way of a retry.
+		if content.Remote != "" {
+			err2 := pulpDelTask(content.Remote)
+			if err2 != nil {
+				fmt.Printf("ERROR %s\n", err2.Error())
+			} else {
+				fmt.Printf("Remote %s removed again.\n", repo)
+			}
+		}
+		return err
+	}
+	fmt.Printf("Repository %s created.\n", repo)
+	return pulpCreateDistributions(repo, info)
+}
+
+func pulpCreateDistributions(repo string, info RepoDetails) error {
+
+	for _, env := range apiEnv {
+		distInfo, err := pulpDistributionInfo(repo + "-" + env)
+		if err != nil {
+			return err
+		}
+		if distInfo.Count > 0 {
+			fmt.Printf("%s repository distribution already exists.\n", env)
+			continue
+		}
+		err = pulpCreateDistribution(repo, info, env, "")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func pulpRepoResources(repo PulpRepository) ([]PulpDistribution, []PulpPublish, error) {
+
+	// Besides the distributions of the environments, these are all
+	// distributions serving the repository or one of its publications.
+	var (
+		dists []PulpDistribution
+		pubs  = make(map[string]bool)
+	)
+
+	publications, err := pulpPublicationList(repo.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, pub := range publications {
+		pubs[pub.Pulp_href] = true
+	}
+	distributions, err := pulpDistributionAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, dist := range distributions {
+		own := false
+		for _, env := range apiEnv {
+			if dist.Name == repo.Name+"-"+env {
+				own = true
+			}
+		}
+		if own || pubs[dist.Publication] || (dist.Repository != "" && dist.Repository == repo.Pulp_href) {
+			dists = append(dists, dist)
+		}
+	}
+	return dists, publications, nil
+}
+
+func pulpDelTask(href string) error {
+
+	req, err := http.NewRequest("DELETE", apiSrv+href, nil)
+	if err != nil {
+		return err
+	}
+	result, status, err := pulpExec(req)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusAccepted {
+		return fmt.Errorf("HTTP response: %d, body: %s", status, string(result))
+	}
+	task := Task{}
+	err = json.Unmarshal(result, &task)
+	if err != nil {
+		return err
+	}
+	_, err = pulpWaitForTask(task)
+	return err
+}
+
+func pulpDelRepo(repo PulpRepository, dists []PulpDistribution, pubs []PulpPublish, remote *PulpRemote) error {
+
+	// The distributions and publications go first, so nothing keeps serving
+	// content of the repository once it is gone.
+	for _, dist := range dists {
+		err := pulpDelTask(dist.Pulp_href)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Distribution %s removed.\n", dist.Name)
+	}
+	for _, pub := range pubs {
+		err := pulpDelPublication(pub)
+		if err != nil {
+			return err
+		}
+	}
+	if len(pubs) > 0 {
+		fmt.Printf("%d publications removed.\n", len(pubs))
+	}
+	err := pulpDelTask(repo.Pulp_href)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Repository %s removed.\n", repo.Name)
+	if remote != nil {
+		err = pulpDelTask(remote.Pulp_href)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Remote %s removed.\n", remote.Name)
+	}
+	return nil
+}
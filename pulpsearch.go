@@ -0,0 +1,282 @@
+/* Pulp CLI
+ *
+ * - Version 1.26.0 - 2026/10/19
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+func searchMatch(term string, value string) bool {
+
+	if term == value {
+		return true
+	}
+	matched, err := path.Match(term, value)
+	return err == nil && matched
+}
+
+func packageProvides(pack PulpContent, term string) bool {
+
+	for _, provide := range pack.Provides {
+		if len(provide) == 0 {
+			continue
+		}
+		name, ok := provide[0].(string)
+		if ok && searchMatch(term, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func packageHasFile(pack PulpContent, term string) bool {
+
+	for _, file := range pack.Files {
+		if len(file) < 3 {
+			continue
+		}
+		if searchMatch(term, file[1]+file[2]) {
+			return true
+		}
+	}
+	return false
+}
+
+func pulpContentSearch(query string) ([]PulpContent, error) {
+
+	var (
+		contentInfo PulpContentResults
+		results     []PulpContent
+	)
+
+	requestString := apiEnd + "/content/rpm/packages/?" + strings.TrimPrefix(query, "&")
+	for requestString != "" {
+		req, err := http.NewRequest("GET", requestString, nil)
+		if err != nil {
+			return nil, err
+		}
+		body, status, err := pulpExec(req)
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("HTTP response: %d", status)
+		}
+		contentInfo = PulpContentResults{
+			Count:    0,
+			Next:     "",
+			Previous: "",
+			Results:  []PulpContent{},
+		}
+		err = json.Unmarshal(body, &contentInfo)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, contentInfo.Results...)
+		requestString = contentInfo.Next
+	}
+	return results, nil
+}
+
+func pulpSearchPackages(term string, mode string) ([]PulpContent, error) {
+
+	var (
+		packs   []PulpContent
+		matches []PulpContent
+		err     error
+	)
+
+	// Pulp can't filter on capabilities or files, so the metadata of all
+	// packages is read.
+	switch mode {
+	case "provides":
+		packs, err = pulpContentSearch("fields=pulp_href,name,epoch,version,release,arch,pkgId,provides")
+		if err != nil {
+			return nil, err
+		}
+		for _, pack := range packs {
+			if packageProvides(pack, term) {
+				matches = append(matches, pack)
+			}
+		}
+	case "file":
+		packs, err = pulpContentSearch("fields=pulp_href,name,epoch,version,release,arch,pkgId,files")
+		if err != nil {
+			return nil, err
+		}
+		for _, pack := range packs {
+			if packageHasFile(pack, term) {
+				matches = append(matches, pack)
+			}
+		}
+	default:
+		if sha256Pattern.MatchString(term) {
+			info, err := pulpPackageContent(term, "")
+			if err != nil {
+				return nil, err
+			}
+			matches = info.Results
+			break
+		}
+		query := "fields=" + PACKAGE_FIELDS
+		prefix := packagePrefix(term)
+		if prefix != "" {
+			query += "&name__startswith=" + url.QueryEscape(prefix)
+		}
+		packs, err = pulpContentSearch(query)
+		if err != nil {
+			return nil, err
+		}
+		for _, pack := range packs {
+			if matchPackage(term, pack) {
+				matches = append(matches, pack)
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return packageNevra(matches[i]) < packageNevra(matches[j])
+	})
+	return matches, nil
+}
+
+func pulpContentVersions(repo PulpRepository, content string) ([]int, error) {
+
+	var (
+		verInfo  PulpRepoVersionResults
+		versions []int
+	)
+
+	requestString := apiSrv + repo.Versions_href + "?content=" + url.QueryEscape(content)
+	for requestString != "" {
+		req, err := http.NewRequest("GET", requestString, nil)
+		if err != nil {
+			return nil, err
+		}
+		body, status, err := pulpExec(req)
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("HTTP response: %d", status)
+		}
+		verInfo = PulpRepoVersionResults{
+			Count:    0,
+			Next:     "",
+			Previous: "",
+			Results:  []PulpRepoVersion{},
+		}
+		err = json.Unmarshal(body, &verInfo)
+		if err != nil {
+			return nil, err
+		}
+		for _, version := range verInfo.Results {
+			versions = append(versions, version.Number)
+		}
+		requestString = verInfo.Next
+	}
+	sort.Ints(versions)
+	return versions, nil
+}
+
+func pulpServedVersions() (map[string][]string, error) {
+
+	served := make(map[string][]string)
+	distributions, err := pulpDistributionAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, dist := range distributions {
+		if dist.Publication == "" && dist.Repository == "" {
+			continue
+		}
+		version, err := pulpDistributionVersion(dist.Name)
+		if err != nil {
+			return nil, err
+		}
+		served[version] = append(served[version], dist.Name)
+	}
+	return served, nil
+}
+
+func pulpSearchLocations(content string, repos []PulpRepository, served map[string][]string) ([]SearchLocation, error) {
+
+	var locations []SearchLocation
+
+	for _, repo := range repos {
+		versions, err := pulpContentVersions(repo, content)
+		if err != nil {
+			return nil, err
+		}
+		if len(versions) == 0 {
+			continue
+		}
+		location := SearchLocation{
+			Repository:   repo.Name,
+			Versions:     versions,
+			Latest:       false,
+			Distribution: []string{},
+		}
+		for _, number := range versions {
+			href := repo.Versions_href + strconv.Itoa(number) + "/"
+			if href == repo.Latest_version_href {
+				location.Latest = true
+			}
+			for _, dist := range served[href] {
+				location.Distribution = append(location.Distribution, dist+" (version "+strconv.Itoa(number)+")")
+			}
+		}
+		sort.Strings(location.Distribution)
+		locations = append(locations, location)
+	}
+	return locations, nil
+}
+
+func versionRanges(versions []int) string {
+
+	// Sorted version numbers are written as ranges, e.g. 3-5, 8.
+	var ranges []string
+
+	for i := 0; i < len(versions); {
+		j := i
+		for j+1 < len(versions) && versions[j+1] == versions[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, strconv.Itoa(versions[i]))
+		} else {
+			ranges = append(ranges, strconv.Itoa(versions[i])+"-"+strconv.Itoa(versions[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(ranges, ", ")
+}
+
+func printSearch(pack PulpContent, locations []SearchLocation) {
+
+	fmt.Printf("%s\t%s\n", packageNevra(pack), pack.PkgId)
+	if len(locations) == 0 {
+		fmt.Printf("\tin no repository\n")
+	}
+	for _, location := range locations {
+		latest := ""
+		if location.Latest {
+			latest = ", latest"
+		}
+		fmt.Printf("\trepository %s: versions %s%s\n", location.Repository, versionRanges(location.Versions), latest)
+		for _, dist := range location.Distribution {
+			fmt.Printf("\t\tserved by %s\n", dist)
+		}
+	}
+}
@@ -1,6 +1,6 @@
 /* Pulp CLI
  *
- * - Version 1.0 - 2021/07/31
+ * - Version 1.24.0 - 2026/10/19
  */
 package main
 
@@ -32,6 +32,9 @@ func pulpSetPubVersion(repository string, distribution string, environment strin
 			break
 		}
 	}
+	if publication == "" {
+		return fmt.Errorf("repository %s has no publication of version %d", repository, version)
+	}
 	distInfo, err := pulpDistributionInfo(distribution)
 	if err != nil {
 		return err
@@ -43,6 +46,7 @@ func pulpSetPubVersion(repository string, distribution string, environment strin
 	content := DistroSet{
 		Base_path:     info.Distribution + "/" + info.Release + "/" + info.Architecture + "/" + info.Name + "/" + environment,
 		Content_guard: "",
+		Pulp_labels:   nil,
 		Name:          distribution,
 		Publication:   publication,
 	}
@@ -1,6 +1,6 @@
 /* Pulp CLI
  *
- * - Version 1.0 - 2021/07/31
+ * - Version 1.20.0 - 2026/10/19
  */
 package main
 
@@ -11,7 +11,7 @@ import (
 	"net/http"
 )
 
-func pulpSyncRepo(repo string) error {
+func pulpSyncRepo(repo string, srpm bool) error {
 
 	repoInfo, err := pulpRepositoryInfo(repo)
 	if err != nil {
@@ -23,11 +23,19 @@ func pulpSyncRepo(repo string) error {
 	if repoInfo.Results[0].Remote == "" {
 		return fmt.Errorf("remote is not set for repo %s", repo)
 	}
+	remote, err := pulpRemoteDetails(repoInfo.Results[0].Remote)
+	if err != nil {
+		return fmt.Errorf("remote of repo %s: %s", repo, err.Error())
+	}
+	fmt.Printf("Syncing repository %s from remote %s at %s.\n", repo, remote.Name, remote.Url)
 	content := SyncSet{
 		Mirror:     true,
 		Skip_types: []string{"srpm"},
 		Optimize:   true,
 	}
+	if srpm {
+		content.Skip_types = []string{}
+	}
 	body, err := json.Marshal(content)
 	if err != nil {
 		return err
@@ -0,0 +1,270 @@
+/* Pulp CLI
+ *
+ * - Version 1.5.0 - 2026/10/19
+ */
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+)
+
+func uploadStateDir() (string, error) {
+
+	osuser, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+	return osuser.HomeDir + "/.pulp/uploads", nil
+}
+
+func uploadStatePath(file string) (string, error) {
+
+	dir, err := uploadStateDir()
+	if err != nil {
+		return "", err
+	}
+	// The state of an upload is stored under the checksum of the absolute
+	// path of the package, so it can be found back on a rerun.
+	h := sha256.Sum256([]byte(file))
+	return filepath.Join(dir, hex.EncodeToString(h[:])+".json"), nil
+}
+
+func loadUploadState(file string) (*UploadState, error) {
+
+	statepath, err := uploadStatePath(file)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(statepath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := &UploadState{}
+	err = json.Unmarshal(content, state)
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func loadUploadStates() ([]*UploadState, error) {
+
+	var states []*UploadState
+
+	dir, err := uploadStateDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		state := &UploadState{}
+		err = json.Unmarshal(content, state)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", entry.Name(), err.Error())
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].File < states[j].File })
+	return states, nil
+}
+
+func saveUploadState(state *UploadState) error {
+
+	statepath, err := uploadStatePath(state.File)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stat(filepath.Dir(statepath))
+	if os.IsNotExist(err) {
+		err = os.MkdirAll(filepath.Dir(statepath), 0700)
+		if err != nil {
+			return err
+		}
+	}
+	output, err := json.MarshalIndent(state, " ", " ")
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first, so an interrupted write can never
+	// leave a corrupt state behind.
+	err = os.WriteFile(statepath+".tmp", output, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(statepath+".tmp", statepath)
+}
+
+func removeUploadState(state *UploadState) error {
+
+	statepath, err := uploadStatePath(state.File)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(statepath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func uploadedBytes(state *UploadState) int64 {
+
+	var total int64
+
+	for _, r := range state.Ranges {
+		total += r.End - r.Start + 1
+	}
+	return total
+}
+
+func uploadCovers(state *UploadState, start, end int64) bool {
+
+	for _, r := range state.Ranges {
+		if r.Start <= start && r.End >= end {
+			return true
+		}
+	}
+	return false
+}
+
+func pulpUploadDetails(upload string) (PulpUploadResults, int, error) {
+
+	var pur = PulpUploadResults{
+		Pulp_href:    "",
+		Pulp_created: "",
+		Size:         0,
+		Completed:    "",
+		Chunks:       []PulpUploadChunk{},
+	}
+
+	req, err := http.NewRequest("GET", apiSrv+upload, nil)
+	if err != nil {
+		return pur, http.StatusBadRequest, err
+	}
+	result, status, err := pulpExec(req)
+	if err != nil {
+		return pur, status, err
+	}
+	if status != http.StatusOK {
+		return pur, status, fmt.Errorf("HTTP response: %d, expected: %d", status, http.StatusOK)
+	}
+	err = json.Unmarshal(result, &pur)
+	if err != nil {
+		return pur, status, err
+	}
+	return pur, status, nil
+}
+
+func pulpResumeUpload(file string, size int64, sha256sum string) (PulpUploadResults, *UploadState, error) {
+
+	var pur PulpUploadResults
+
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return pur, nil, err
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return pur, nil, err
+	}
+	mtime := info.ModTime().UnixNano()
+	state, err := loadUploadState(file)
+	if err != nil {
+		return pur, nil, err
+	}
+	if state != nil {
+		if state.Size == size && state.Mtime == mtime && state.Sha256 == sha256sum {
+			pur, status, err := pulpUploadDetails(state.Upload)
+			if err == nil {
+				// Chunks the server already holds don't need to be sent
+				// again, even if we didn't get to record them.
+				for _, chunk := range pur.Chunks {
+					if !uploadCovers(state, chunk.Offset, chunk.Offset+chunk.Size-1) {
+						state.Ranges = append(state.Ranges, UploadRange{
+							Start: chunk.Offset,
+							End:   chunk.Offset + chunk.Size - 1,
+						})
+					}
+				}
+				fmt.Printf("Resuming upload of %s, %d of %d bytes already uploaded.\n", file, uploadedBytes(state), size)
+				return pur, state, nil
+			}
+			if status != http.StatusNotFound {
+				return pur, nil, err
+			}
+		} else {
+			// The package changed since the upload was started, so the
+			// chunks on the server are worthless.
+			pur.Pulp_href = state.Upload
+			err = pulpDeinitUpload(pur)
+			if err != nil {
+				fmt.Printf("WARNING could not remove stale upload %s: %s\n", state.Upload, err.Error())
+			}
+		}
+		err = removeUploadState(state)
+		if err != nil {
+			return pur, nil, err
+		}
+	}
+	pur, err = pulpInitUpload(size)
+	if err != nil {
+		return pur, nil, err
+	}
+	state = &UploadState{
+		Upload: pur.Pulp_href,
+		File:   file,
+		Size:   size,
+		Mtime:  mtime,
+		Sha256: sha256sum,
+		Ranges: []UploadRange{},
+	}
+	err = saveUploadState(state)
+	if err != nil {
+		return pur, nil, err
+	}
+	return pur, state, nil
+}
+
+func pulpAbortUpload(state *UploadState) error {
+
+	pur := PulpUploadResults{
+		Pulp_href:    state.Upload,
+		Pulp_created: "",
+		Size:         0,
+		Completed:    "",
+		Chunks:       []PulpUploadChunk{},
+	}
+	err := pulpDeinitUpload(pur)
+	if err != nil {
+		// An upload that no longer exists on the server was already aborted.
+		_, status, err2 := pulpUploadDetails(state.Upload)
+		if err2 == nil || status != http.StatusNotFound {
+			return err
+		}
+	}
+	return removeUploadState(state)
+}
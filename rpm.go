@@ -0,0 +1,618 @@
+/* Pulp CLI
+ *
+ * - Version 1.17.0 - 2026/10/19
+ */
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	RPM_LEAD_SIZE   int    = 96
+	RPM_LEAD_MAGIC  uint32 = 0xedabeedb
+	RPM_HDR_MAGIC   uint32 = 0x8eade801
+	RPM_MAX_HEADER  int    = 256 * 1024 * 1024 // Sanity limit on the size of a header.
+	RPM_TYPE_SOURCE uint16 = 1
+
+	// Header data types
+	RPM_INT8_TYPE         uint32 = 2
+	RPM_INT16_TYPE        uint32 = 3
+	RPM_INT32_TYPE        uint32 = 4
+	RPM_INT64_TYPE        uint32 = 5
+	RPM_STRING_TYPE       uint32 = 6
+	RPM_BIN_TYPE          uint32 = 7
+	RPM_STRING_ARRAY_TYPE uint32 = 8
+	RPM_I18NSTRING_TYPE   uint32 = 9
+
+	// Header tags
+	RPMTAG_NAME      uint32 = 1000
+	RPMTAG_VERSION   uint32 = 1001
+	RPMTAG_RELEASE   uint32 = 1002
+	RPMTAG_EPOCH     uint32 = 1003
+	RPMTAG_ARCH      uint32 = 1022
+	RPMTAG_SOURCERPM uint32 = 1044
+
+	RPMTAG_PAYLOADDIGEST     uint32 = 5092
+	RPMTAG_PAYLOADDIGESTALGO uint32 = 5093
+
+	// Signature header tags
+	RPMSIGTAG_DSA uint32 = 267  // DSA signature of the header.
+	RPMSIGTAG_RSA uint32 = 268  // RSA signature of the header.
+	RPMSIGTAG_PGP uint32 = 1002 // RSA signature of the header and payload.
+	RPMSIGTAG_GPG uint32 = 1005 // DSA signature of the header and payload.
+
+	RPMSIGTAG_SHA1     uint32 = 269  // SHA1 digest of the header.
+	RPMSIGTAG_LONGSIZE uint32 = 270  // Size of the header and payload.
+	RPMSIGTAG_SHA256   uint32 = 273  // SHA256 digest of the header.
+	RPMSIGTAG_SIZE     uint32 = 1000 // Size of the header and payload.
+	RPMSIGTAG_MD5      uint32 = 1004 // MD5 digest of the header and payload.
+
+	// Signature policies
+	SIG_POLICY_OFF             string = "off"
+	SIG_POLICY_WARN            string = "warn"
+	SIG_POLICY_REJECT_UNSIGNED string = "reject-unsigned"
+	SIG_POLICY_REJECT_UNKNOWN  string = "reject-unknown"
+)
+
+func readRpmHeader(reader io.Reader, signature bool) (RpmHeader, error) {
+
+	var header RpmHeader
+
+	preamble := make([]byte, 16)
+	_, err := io.ReadFull(reader, preamble)
+	if err != nil {
+		return header, fmt.Errorf("truncated header: %s", err.Error())
+	}
+	if binary.BigEndian.Uint32(preamble[0:4]) != RPM_HDR_MAGIC {
+		return header, fmt.Errorf("bad header magic")
+	}
+	nindex := int(binary.BigEndian.Uint32(preamble[8:12]))
+	hsize := int(binary.BigEndian.Uint32(preamble[12:16]))
+	if nindex < 0 || hsize < 0 || nindex*16+hsize > RPM_MAX_HEADER {
+		return header, fmt.Errorf("header too large")
+	}
+	size := 16 + nindex*16 + hsize
+	// The signature header is padded to a multiple of 8 bytes.
+	padding := 0
+	if signature && size%8 != 0 {
+		padding = 8 - size%8
+	}
+	header.Raw = make([]byte, size)
+	copy(header.Raw, preamble)
+	_, err = io.ReadFull(reader, header.Raw[16:size])
+	if err != nil {
+		return header, fmt.Errorf("truncated header: %s", err.Error())
+	}
+	if padding > 0 {
+		_, err = io.ReadFull(reader, make([]byte, padding))
+		if err != nil {
+			return header, fmt.Errorf("truncated header: %s", err.Error())
+		}
+	}
+	header.Data = header.Raw[16+nindex*16:]
+	for i := 0; i < nindex; i++ {
+		entry := header.Raw[16+i*16 : 32+i*16]
+		e := RpmHeaderEntry{
+			Tag:    binary.BigEndian.Uint32(entry[0:4]),
+			Type:   binary.BigEndian.Uint32(entry[4:8]),
+			Offset: binary.BigEndian.Uint32(entry[8:12]),
+			Count:  binary.BigEndian.Uint32(entry[12:16]),
+		}
+		if int(e.Offset) > len(header.Data) {
+			return header, fmt.Errorf("header tag %d points outside of the header", e.Tag)
+		}
+		header.Index = append(header.Index, e)
+	}
+	return header, nil
+}
+
+func rpmHeaderEntry(header RpmHeader, tag uint32) (RpmHeaderEntry, bool) {
+
+	for _, e := range header.Index {
+		if e.Tag == tag {
+			return e, true
+		}
+	}
+	return RpmHeaderEntry{}, false
+}
+
+func rpmHeaderStrings(header RpmHeader, tag uint32) []string {
+
+	var result []string
+
+	e, ok := rpmHeaderEntry(header, tag)
+	if !ok {
+		return nil
+	}
+	if e.Type != RPM_STRING_TYPE && e.Type != RPM_STRING_ARRAY_TYPE && e.Type != RPM_I18NSTRING_TYPE {
+		return nil
+	}
+	data := header.Data[e.Offset:]
+	for i := uint32(0); i < e.Count; i++ {
+		end := bytes.IndexByte(data, 0)
+		if end < 0 {
+			break
+		}
+		result = append(result, string(data[:end]))
+		data = data[end+1:]
+		// A plain string has a count of 1.
+		if e.Type == RPM_STRING_TYPE {
+			break
+		}
+	}
+	return result
+}
+
+func rpmHeaderString(header RpmHeader, tag uint32) string {
+
+	values := rpmHeaderStrings(header, tag)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func rpmHeaderInts(header RpmHeader, tag uint32) []int64 {
+
+	var (
+		result []int64
+		width  int
+	)
+
+	e, ok := rpmHeaderEntry(header, tag)
+	if !ok {
+		return nil
+	}
+	switch e.Type {
+	case RPM_INT8_TYPE:
+		width = 1
+	case RPM_INT16_TYPE:
+		width = 2
+	case RPM_INT32_TYPE:
+		width = 4
+	case RPM_INT64_TYPE:
+		width = 8
+	default:
+		return nil
+	}
+	data := header.Data[e.Offset:]
+	for i := 0; i < int(e.Count) && (i+1)*width <= len(data); i++ {
+		value := data[i*width : (i+1)*width]
+		switch width {
+		case 1:
+			result = append(result, int64(value[0]))
+		case 2:
+			result = append(result, int64(binary.BigEndian.Uint16(value)))
+		case 4:
+			result = append(result, int64(binary.BigEndian.Uint32(value)))
+		case 8:
+			result = append(result, int64(binary.BigEndian.Uint64(value)))
+		}
+	}
+	return result
+}
+
+func rpmHeaderBin(header RpmHeader, tag uint32) []byte {
+
+	e, ok := rpmHeaderEntry(header, tag)
+	if !ok || e.Type != RPM_BIN_TYPE {
+		return nil
+	}
+	end := int(e.Offset) + int(e.Count)
+	if end > len(header.Data) {
+		return nil
+	}
+	return header.Data[e.Offset:end]
+}
+
+func readRpmPackage(file string, checksum bool) (RpmPackage, error) {
+
+	// When checksum is set, the whole file is read in a single pass to
+	// calculate the pkgId (sha256) as well.
+	var pkg RpmPackage
+
+	f, err := os.Open(file)
+	if err != nil {
+		return pkg, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return pkg, err
+	}
+	pkg.Size = info.Size()
+	var (
+		reader   io.Reader = f
+		h                  = sha256.New()
+		h512               = sha512.New()
+		progress *Progress
+	)
+	if checksum {
+		progress = newProgress("Reading "+filepath.Base(file), pkg.Size, 0)
+		defer progressDone(progress)
+		reader = io.TeeReader(newProgressReader(progress, f), io.MultiWriter(h, h512))
+	}
+	buffered := bufio.NewReader(reader)
+	lead := make([]byte, RPM_LEAD_SIZE)
+	_, err = io.ReadFull(buffered, lead)
+	if err != nil {
+		return pkg, fmt.Errorf("%s is not an rpm package", file)
+	}
+	if binary.BigEndian.Uint32(lead[0:4]) != RPM_LEAD_MAGIC {
+		return pkg, fmt.Errorf("%s is not an rpm package", file)
+	}
+	pkg.Source = binary.BigEndian.Uint16(lead[6:8]) == RPM_TYPE_SOURCE
+	pkg.Signature, err = readRpmHeader(buffered, true)
+	if err != nil {
+		return pkg, fmt.Errorf("%s: signature %s", file, err.Error())
+	}
+	pkg.Header, err = readRpmHeader(buffered, false)
+	if err != nil {
+		return pkg, fmt.Errorf("%s: %s", file, err.Error())
+	}
+	sigsize := len(pkg.Signature.Raw)
+	if sigsize%8 != 0 {
+		sigsize += 8 - sigsize%8
+	}
+	pkg.Payload = int64(RPM_LEAD_SIZE + sigsize + len(pkg.Header.Raw))
+	pkg.Name = rpmHeaderString(pkg.Header, RPMTAG_NAME)
+	pkg.Version = rpmHeaderString(pkg.Header, RPMTAG_VERSION)
+	pkg.Release = rpmHeaderString(pkg.Header, RPMTAG_RELEASE)
+	pkg.Arch = rpmHeaderString(pkg.Header, RPMTAG_ARCH)
+	pkg.SourceRpm = rpmHeaderString(pkg.Header, RPMTAG_SOURCERPM)
+	// Pulp stores a missing epoch as 0.
+	pkg.Epoch = "0"
+	epoch := rpmHeaderInts(pkg.Header, RPMTAG_EPOCH)
+	if len(epoch) > 0 {
+		pkg.Epoch = strconv.FormatInt(epoch[0], 10)
+	}
+	if pkg.Name == "" || pkg.Version == "" || pkg.Release == "" || pkg.Arch == "" {
+		return pkg, fmt.Errorf("%s: header lacks name, version, release or arch", file)
+	}
+	// The arch of a source package is the one it was built on, but Pulp
+	// and yum know all source packages as arch src.
+	if pkg.Source || pkg.SourceRpm == "" {
+		pkg.Source = true
+		pkg.Arch = "src"
+	}
+	if checksum {
+		// While reading the payload anyway, its digests are verified.
+		whole := md5.New()
+		whole.Write(pkg.Header.Raw)
+		algo := crypto.SHA256
+		algos := rpmHeaderInts(pkg.Header, RPMTAG_PAYLOADDIGESTALGO)
+		if len(algos) > 0 {
+			algo, err = pgpHash(byte(algos[0]))
+			if err != nil {
+				return pkg, fmt.Errorf("%s: payload %s", file, err.Error())
+			}
+		}
+		payload := algo.New()
+		size, err := io.Copy(io.MultiWriter(whole, payload), buffered)
+		if err != nil {
+			return pkg, err
+		}
+		progressDone(progress)
+		pkg.PkgId = hex.EncodeToString(h.Sum(nil))
+		pkg.Sha512 = hex.EncodeToString(h512.Sum(nil))
+		pkg.DigestErr = checkRpmDigests(pkg, int64(len(pkg.Header.Raw))+size, whole.Sum(nil), payload.Sum(nil))
+	}
+	return pkg, nil
+}
+
+func checkRpmDigests(pkg RpmPackage, size int64, whole []byte, payload []byte) error {
+
+	checked := false
+	sha1sum := sha1.Sum(pkg.Header.Raw)
+	sha256sum := sha256.Sum256(pkg.Header.Raw)
+	expected := rpmHeaderString(pkg.Signature, RPMSIGTAG_SHA256)
+	if expected != "" {
+		if expected != hex.EncodeToString(sha256sum[:]) {
+			return fmt.Errorf("header SHA256 digest mismatch")
+		}
+		checked = true
+	}
+	expected = rpmHeaderString(pkg.Signature, RPMSIGTAG_SHA1)
+	if expected != "" {
+		if expected != hex.EncodeToString(sha1sum[:]) {
+			return fmt.Errorf("header SHA1 digest mismatch")
+		}
+		checked = true
+	}
+	sizes := rpmHeaderInts(pkg.Signature, RPMSIGTAG_LONGSIZE)
+	if len(sizes) == 0 {
+		sizes = rpmHeaderInts(pkg.Signature, RPMSIGTAG_SIZE)
+	}
+	if len(sizes) > 0 && sizes[0] != size {
+		return fmt.Errorf("package size mismatch, expected %d bytes got %d", sizes[0], size)
+	}
+	md5sum := rpmHeaderBin(pkg.Signature, RPMSIGTAG_MD5)
+	if md5sum != nil {
+		if !bytes.Equal(md5sum, whole) {
+			return fmt.Errorf("header and payload MD5 digest mismatch")
+		}
+		checked = true
+	}
+	digests := rpmHeaderStrings(pkg.Header, RPMTAG_PAYLOADDIGEST)
+	if len(digests) > 0 {
+		if digests[0] != hex.EncodeToString(payload) {
+			return fmt.Errorf("payload digest mismatch")
+		}
+		checked = true
+	}
+	if !checked {
+		return fmt.Errorf("package has no digests")
+	}
+	return nil
+}
+
+var rpmDistTag = regexp.MustCompile(`\.(el|fc)(\d+)`)
+
+func checkRpmRepository(pkg RpmPackage, repo RepoDetails) error {
+
+	// Source packages are kept alongside binary ones, like noarch packages,
+	// but a source repository holds only source packages.
+	switch {
+	case repo.Architecture == "src" && pkg.Arch != "src":
+		return fmt.Errorf("binary package in source repository")
+	case pkg.Arch != "src" && pkg.Arch != "noarch" && pkg.Arch != repo.Architecture:
+		return fmt.Errorf("arch %s does not fit repository arch %s", pkg.Arch, repo.Architecture)
+	}
+	// Packages without a recognizable dist tag are accepted.
+	tag := rpmDistTag.FindStringSubmatch(pkg.Release)
+	if tag == nil {
+		return nil
+	}
+	prefix := "el"
+	if repo.Distribution == "fedora" {
+		prefix = "fc"
+	}
+	if tag[1] != prefix || tag[2] != repo.Release {
+		return fmt.Errorf("dist tag %s%s does not fit repository release %s%s", tag[1], tag[2], prefix, repo.Release)
+	}
+	return nil
+}
+
+func rpmIsDigit(c byte) bool {
+
+	return c >= '0' && c <= '9'
+}
+
+func rpmIsAlnum(c byte) bool {
+
+	return rpmIsDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func rpmVerCmp(a, b string) int {
+
+	if a == b {
+		return 0
+	}
+	for {
+		for len(a) > 0 && !rpmIsAlnum(a[0]) && a[0] != '~' && a[0] != '^' {
+			a = a[1:]
+		}
+		for len(b) > 0 && !rpmIsAlnum(b[0]) && b[0] != '~' && b[0] != '^' {
+			b = b[1:]
+		}
+		// A tilde sorts before anything, even the end of the string.
+		if (len(a) > 0 && a[0] == '~') || (len(b) > 0 && b[0] == '~') {
+			if len(a) == 0 || a[0] != '~' {
+				return 1
+			}
+			if len(b) == 0 || b[0] != '~' {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+		// A caret sorts after the end of the string, but before anything
+		// else.
+		if (len(a) > 0 && a[0] == '^') || (len(b) > 0 && b[0] == '^') {
+			if len(a) == 0 {
+				return -1
+			}
+			if len(b) == 0 {
+				return 1
+			}
+			if a[0] != '^' {
+				return 1
+			}
+			if b[0] != '^' {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+		if len(a) == 0 || len(b) == 0 {
+			break
+		}
+		// Compare the next segment of digits or letters.
+		numeric := rpmIsDigit(a[0])
+		i, j := 0, 0
+		for i < len(a) && rpmIsAlnum(a[i]) && rpmIsDigit(a[i]) == numeric {
+			i++
+		}
+		for j < len(b) && rpmIsAlnum(b[j]) && rpmIsDigit(b[j]) == numeric {
+			j++
+		}
+		sega, segb := a[:i], b[:j]
+		a, b = a[i:], b[j:]
+		if len(segb) == 0 {
+			// A numeric segment is always newer than an alpha one.
+			if numeric {
+				return 1
+			}
+			return -1
+		}
+		if numeric {
+			sega = strings.TrimLeft(sega, "0")
+			segb = strings.TrimLeft(segb, "0")
+			if len(sega) != len(segb) {
+				if len(sega) > len(segb) {
+					return 1
+				}
+				return -1
+			}
+		}
+		if c := strings.Compare(sega, segb); c != 0 {
+			return c
+		}
+	}
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	if len(a) > 0 {
+		return 1
+	}
+	return -1
+}
+
+func rpmEvrCmp(e1, v1, r1, e2, v2, r2 string) int {
+
+	epoch1, _ := strconv.Atoi(e1)
+	epoch2, _ := strconv.Atoi(e2)
+	if epoch1 != epoch2 {
+		if epoch1 > epoch2 {
+			return 1
+		}
+		return -1
+	}
+	if c := rpmVerCmp(v1, v2); c != 0 {
+		return c
+	}
+	return rpmVerCmp(r1, r2)
+}
+
+func rpmNevra(name, epoch, version, release, arch string) string {
+
+	if epoch != "" && epoch != "0" {
+		return fmt.Sprintf("%s-%s:%s-%s.%s", name, epoch, version, release, arch)
+	}
+	return fmt.Sprintf("%s-%s-%s.%s", name, version, release, arch)
+}
+
+func verifyRpmSignature(file string, pkg RpmPackage, keyring []PgpKey) RpmSignature {
+
+	var (
+		result = RpmSignature{
+			Signed: false,
+			Known:  false,
+			KeyId:  "",
+			UserId: "",
+			Err:    nil,
+		}
+		key     PgpKey
+		payload = true
+	)
+
+	// A signature over header and payload is preferred over one that only
+	// covers the header.
+	blob := rpmHeaderBin(pkg.Signature, RPMSIGTAG_PGP)
+	if blob == nil {
+		blob = rpmHeaderBin(pkg.Signature, RPMSIGTAG_GPG)
+	}
+	if blob == nil {
+		payload = false
+		blob = rpmHeaderBin(pkg.Signature, RPMSIGTAG_RSA)
+	}
+	if blob == nil {
+		blob = rpmHeaderBin(pkg.Signature, RPMSIGTAG_DSA)
+	}
+	if blob == nil {
+		return result
+	}
+	result.Signed = true
+	packets, err := readPgpPackets(blob)
+	if err != nil || len(packets) == 0 || packets[0].Tag != PGP_TAG_SIGNATURE {
+		result.Err = fmt.Errorf("invalid signature packet")
+		return result
+	}
+	sig, err := readPgpSignature(packets[0].Body)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.KeyId = fmt.Sprintf("%016x", sig.KeyId)
+	for _, k := range keyring {
+		if k.KeyId == sig.KeyId {
+			key = k
+			result.Known = true
+			result.UserId = k.UserId
+			break
+		}
+	}
+	if !result.Known {
+		return result
+	}
+	h, err := pgpSignatureHash(sig)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	h.Write(pkg.Header.Raw)
+	if payload {
+		f, err := os.Open(file)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		defer f.Close()
+		_, err = f.Seek(pkg.Payload, io.SeekStart)
+		if err == nil {
+			_, err = io.Copy(h, f)
+		}
+		if err != nil {
+			result.Err = err
+			return result
+		}
+	}
+	result.Err = verifyPgpSignature(sig, key, h)
+	return result
+}
+
+func checkSignaturePolicy(file string, policy string, signature RpmSignature) error {
+
+	var problem error
+
+	switch {
+	case !signature.Signed:
+		problem = fmt.Errorf("%s is not signed", file)
+		if policy == SIG_POLICY_WARN {
+			break
+		}
+		return problem
+	case !signature.Known:
+		problem = fmt.Errorf("%s is signed with unknown key %s", file, signature.KeyId)
+		if policy != SIG_POLICY_REJECT_UNKNOWN {
+			break
+		}
+		return problem
+	case signature.Err != nil:
+		problem = fmt.Errorf("%s has a bad signature from key %s: %s", file, signature.KeyId, signature.Err.Error())
+		if policy == SIG_POLICY_WARN {
+			break
+		}
+		return problem
+	}
+	if problem != nil {
+		fmt.Printf("WARNING %s\n", problem.Error())
+	}
+	return nil
+}
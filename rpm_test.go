@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// The packages in testdata are tiny hand made RPMs whose payload is plain
+// text. Each one holds header and payload digests and is signed with one
+// of the keys in testdata/RPM-GPG-KEY-pulp-admin-test, over the header
+// (RPMSIGTAG_RSA) as well as over header and payload (RPMSIGTAG_PGP).
+// All use SHA256, except testRpmSha1.
+const (
+	testRpmEd25519 = "testdata/pulp-admin-test-1.0-1.el8.noarch.rpm"
+	testRpmRsa     = "testdata/pulp-admin-test-1.0-2.el8.noarch.rpm"
+	testRpmEcdsa   = "testdata/pulp-admin-test-1.0-3.el8.noarch.rpm"
+	testRpmDsa     = "testdata/pulp-admin-test-1.0-4.el8.noarch.rpm"
+	testRpmSha1    = "testdata/pulp-admin-test-1.0-5.el8.noarch.rpm"
+	testKeyring    = "testdata/RPM-GPG-KEY-pulp-admin-test"
+)
+
+// These are signed with subkeys of the first key in
+// testdata/RPM-GPG-KEY-pulp-admin-subkeys, which only certifies. Of its
+// signing subkeys one is revoked and one has expired. The other keys in
+// that file are an expired and a revoked primary key.
+const (
+	testRpmSubkey        = "testdata/pulp-admin-test-1.0-6.el8.noarch.rpm"
+	testRpmRevokedSubkey = "testdata/pulp-admin-test-1.0-7.el8.noarch.rpm"
+	testRpmExpiredSubkey = "testdata/pulp-admin-test-1.0-8.el8.noarch.rpm"
+	testKeyringSubkeys   = "testdata/RPM-GPG-KEY-pulp-admin-subkeys"
+)
+
+func TestRpmVerCmp(t *testing.T) {
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "2.0", -1},
+		{"2.0", "1.0", 1},
+		{"2.0.1", "2.0", 1},
+		{"2.0", "2.0.1", -1},
+		{"10", "9", 1},
+		{"1_0", "1.0", 0},
+		// Leading zeros are ignored.
+		{"1.01", "1.1", 0},
+		{"1.001", "1.1", 0},
+		{"1.010", "1.9", 1},
+		{"0010", "10", 0},
+		// A numeric segment is newer than an alpha one.
+		{"1.a", "1.1", -1},
+		{"1.1", "1.a", 1},
+		{"1.0a", "1.0.1", -1},
+		{"1.0a", "1.0", 1},
+		{"abc", "abd", -1},
+		{"B", "a", -1},
+		// A tilde sorts before anything, even the end of the string.
+		{"1.0~rc1", "1.0", -1},
+		{"1.0", "1.0~rc1", 1},
+		{"1.0~rc1", "1.0~rc2", -1},
+		{"1.0~~", "1.0~", -1},
+		{"1.0~rc1", "1.0~rc1", 0},
+		// A caret sorts after the end of the string, before anything else.
+		{"1.0^", "1.0", 1},
+		{"1.0^git1", "1.0", 1},
+		{"1.0^git1", "1.0.1", -1},
+		{"1.0^git1", "1.0~rc1", 1},
+		{"1.0^git1", "1.0^git2", -1},
+		{"1.0^git1~pre", "1.0^git1", -1},
+	}
+	for _, test := range tests {
+		got := rpmVerCmp(test.a, test.b)
+		if got != test.want {
+			t.Errorf("rpmVerCmp(%q, %q) = %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestRpmEvrCmp(t *testing.T) {
+
+	tests := []struct {
+		e1, v1, r1 string
+		e2, v2, r2 string
+		want       int
+	}{
+		{"0", "1.0", "1.el8", "0", "1.0", "1.el8", 0},
+		{"0", "1.0", "1.el8", "0", "1.0", "2.el8", -1},
+		{"0", "1.1", "1.el8", "0", "1.0", "9.el8", 1},
+		// The epoch wins over version and release.
+		{"1", "1.0", "1", "0", "2.0", "1", 1},
+		{"0", "9.0", "1", "2", "1.0", "1", -1},
+		// A missing epoch is 0.
+		{"", "1.0", "1", "0", "1.0", "1", 0},
+		{"", "1.0", "1", "1", "1.0", "1", -1},
+	}
+	for _, test := range tests {
+		got := rpmEvrCmp(test.e1, test.v1, test.r1, test.e2, test.v2, test.r2)
+		if got != test.want {
+			t.Errorf("rpmEvrCmp(%s:%s-%s, %s:%s-%s) = %d, want %d", test.e1, test.v1, test.r1, test.e2, test.v2, test.r2, got, test.want)
+		}
+	}
+}
+
+// testRpmHeader builds a header holding a single string tag.
+func testRpmHeader(tag uint32, value string) []byte {
+
+	data := append([]byte(value), 0)
+	header := make([]byte, 32)
+	binary.BigEndian.PutUint32(header[0:4], RPM_HDR_MAGIC)
+	binary.BigEndian.PutUint32(header[8:12], 1)
+	binary.BigEndian.PutUint32(header[12:16], uint32(len(data)))
+	binary.BigEndian.PutUint32(header[16:20], tag)
+	binary.BigEndian.PutUint32(header[20:24], RPM_STRING_TYPE)
+	binary.BigEndian.PutUint32(header[24:28], 0)
+	binary.BigEndian.PutUint32(header[28:32], 1)
+	return append(header, data...)
+}
+
+func TestReadRpmHeader(t *testing.T) {
+
+	raw := testRpmHeader(RPMTAG_NAME, "pulp-admin-test")
+	header, err := readRpmHeader(bytes.NewReader(raw), false)
+	if err != nil {
+		t.Fatalf("readRpmHeader: %s", err.Error())
+	}
+	if got := rpmHeaderString(header, RPMTAG_NAME); got != "pulp-admin-test" {
+		t.Errorf("name = %q, want %q", got, "pulp-admin-test")
+	}
+	if !bytes.Equal(header.Raw, raw) {
+		t.Errorf("raw header differs from its input")
+	}
+}
+
+func TestReadRpmHeaderMalformed(t *testing.T) {
+
+	// At 43 bytes, a signature header needs padding.
+	valid := testRpmHeader(RPMTAG_NAME, "pulp-admin")
+	mutate := func(offset int, value uint32) []byte {
+		raw := append([]byte{}, valid...)
+		binary.BigEndian.PutUint32(raw[offset:offset+4], value)
+		return raw
+	}
+	tests := []struct {
+		name      string
+		raw       []byte
+		signature bool
+	}{
+		{"empty", nil, false},
+		{"truncated preamble", valid[:8], false},
+		{"bad magic", mutate(0, 0xdeadbeef), false},
+		{"huge index", mutate(8, 0xffffffff), false},
+		{"huge data", mutate(12, 0xffffffff), false},
+		{"truncated index", valid[:24], false},
+		{"truncated data", valid[:len(valid)-1], false},
+		{"offset outside of data", mutate(24, uint32(len(valid))), false},
+		{"missing signature padding", valid, true},
+	}
+	for _, test := range tests {
+		_, err := readRpmHeader(bytes.NewReader(test.raw), test.signature)
+		if err == nil {
+			t.Errorf("%s: readRpmHeader returned no error", test.name)
+		}
+	}
+	// Every truncation of a valid header must be rejected.
+	for n := 0; n < len(valid); n++ {
+		_, err := readRpmHeader(bytes.NewReader(valid[:n]), false)
+		if err == nil {
+			t.Errorf("readRpmHeader accepted a header truncated to %d of %d bytes", n, len(valid))
+		}
+	}
+}
+
+func TestRpmHeaderValuesOutOfBounds(t *testing.T) {
+
+	header := RpmHeader{
+		Raw: nil,
+		Index: []RpmHeaderEntry{
+			{Tag: RPMTAG_NAME, Type: RPM_STRING_TYPE, Offset: 4, Count: 1},
+			{Tag: RPMTAG_EPOCH, Type: RPM_INT32_TYPE, Offset: 2, Count: 1000},
+			{Tag: RPMSIGTAG_MD5, Type: RPM_BIN_TYPE, Offset: 0, Count: 1000},
+			{Tag: RPMTAG_ARCH, Type: RPM_STRING_ARRAY_TYPE, Offset: 0, Count: 1000},
+		},
+		Data: []byte{'a', 0, 0, 0, 'n', 'o', 'n', 'u', 'l'},
+	}
+	if got := rpmHeaderString(header, RPMTAG_NAME); got != "" {
+		t.Errorf("unterminated string = %q, want none", got)
+	}
+	if got := rpmHeaderInts(header, RPMTAG_EPOCH); len(got) != 1 {
+		t.Errorf("ints beyond the data = %v, want only the one that fits", got)
+	}
+	if got := rpmHeaderBin(header, RPMSIGTAG_MD5); got != nil {
+		t.Errorf("binary beyond the data = %v, want none", got)
+	}
+	if got := rpmHeaderStrings(header, RPMTAG_ARCH); len(got) != 3 {
+		t.Errorf("string array beyond the data = %q, want the 3 terminated ones", got)
+	}
+}
+
+func TestReadRpmPackage(t *testing.T) {
+
+	content, err := os.ReadFile(testRpmEd25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := readRpmPackage(testRpmEd25519, true)
+	if err != nil {
+		t.Fatalf("readRpmPackage: %s", err.Error())
+	}
+	if got := rpmNevra(pkg.Name, pkg.Epoch, pkg.Version, pkg.Release, pkg.Arch); got != "pulp-admin-test-1.0-1.el8.noarch" {
+		t.Errorf("nevra = %s", got)
+	}
+	if pkg.Epoch != "0" || pkg.Source {
+		t.Errorf("epoch %q and source %t, want 0 and false", pkg.Epoch, pkg.Source)
+	}
+	if pkg.DigestErr != nil {
+		t.Errorf("digests: %s", pkg.DigestErr.Error())
+	}
+	sum := sha256.Sum256(content)
+	if pkg.PkgId != hex.EncodeToString(sum[:]) {
+		t.Errorf("pkgId %s, want %s", pkg.PkgId, hex.EncodeToString(sum[:]))
+	}
+}
+
+func TestReadRpmPackageTruncated(t *testing.T) {
+
+	content, err := os.ReadFile(testRpmEd25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := readRpmPackage(testRpmEd25519, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "truncated.rpm")
+	for n := 0; n < len(content); n++ {
+		err = os.WriteFile(file, content[:n], 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		truncated, err := readRpmPackage(file, true)
+		switch {
+		case int64(n) < pkg.Payload && err == nil:
+			t.Errorf("truncated to %d bytes: headers accepted", n)
+		case int64(n) >= pkg.Payload && err != nil:
+			t.Errorf("truncated to %d bytes: %s", n, err.Error())
+		case int64(n) >= pkg.Payload && truncated.DigestErr == nil:
+			t.Errorf("truncated to %d bytes: digests accepted", n)
+		}
+	}
+}
+
+func TestReadRpmPackageCorrupt(t *testing.T) {
+
+	content, err := os.ReadFile(testRpmEd25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content[len(content)-1] ^= 0xff
+	file := filepath.Join(t.TempDir(), "corrupt.rpm")
+	err = os.WriteFile(file, content, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := readRpmPackage(file, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.DigestErr == nil {
+		t.Errorf("a corrupt payload passed the digest checks")
+	}
+}
+
+func TestCheckRpmRepository(t *testing.T) {
+
+	binary := RepoDetails{Name: "myrepo", Distribution: "rh", Release: "8", Architecture: "x86_64"}
+	source := RepoDetails{Name: "myrepo", Distribution: "rh", Release: "8", Architecture: "src"}
+	fedora := RepoDetails{Name: "myrepo", Distribution: "fedora", Release: "38", Architecture: "x86_64"}
+	tests := []struct {
+		arch    string
+		release string
+		repo    RepoDetails
+		accept  bool
+	}{
+		{"x86_64", "1.el8", binary, true},
+		{"noarch", "1.el8", binary, true},
+		{"src", "1.el8", binary, true},
+		{"aarch64", "1.el8", binary, false},
+		{"x86_64", "1.el9", binary, false},
+		{"src", "1.el9", binary, false},
+		{"x86_64", "1", binary, true},
+		{"src", "1.el8", source, true},
+		{"x86_64", "1.el8", source, false},
+		{"noarch", "1.el8", source, false},
+		{"x86_64", "1.fc38", fedora, true},
+		{"x86_64", "1.el8", fedora, false},
+	}
+	for _, test := range tests {
+		pkg := RpmPackage{Name: "test", Version: "1.0", Release: test.release, Arch: test.arch}
+		err := checkRpmRepository(pkg, test.repo)
+		if (err == nil) != test.accept {
+			t.Errorf("%s.%s in %s repository: error %v", test.release, test.arch, test.repo.Architecture, err)
+		}
+	}
+}
@@ -1,15 +1,73 @@
 /* Pulp CLI
  *
- * - Version 1.1.0 - 2021/08/19
+ * - Version 1.26.0 - 2026/10/19
  */
 package main
 
-import "sync"
+import (
+	"context"
+	"crypto"
+	"io"
+	"sync"
+	"time"
+)
 
-type Thread struct {
-	mutex sync.Mutex
-	count int
-	err   error
+type Progress struct {
+	label   string
+	total   int64
+	done    int64
+	initial int64
+	message string
+	start   time.Time
+	last    time.Time
+	tty     bool
+}
+
+type ProgressReader struct {
+	reader   io.Reader
+	progress *Progress
+	count    int64
+}
+
+type ChunkPool struct {
+	mutex    sync.Mutex
+	err      error
+	sent     int64
+	state    *UploadState
+	progress *Progress
+	cancel   context.CancelFunc
+}
+
+type UploadRange struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+type UploadState struct {
+	Upload string        `json:"upload"`
+	File   string        `json:"file"`
+	Size   int64         `json:"size"`
+	Mtime  int64         `json:"mtime"`
+	Sha256 string        `json:"sha256"`
+	Ranges []UploadRange `json:"ranges"`
+}
+
+type PackageSource struct {
+	Origin string // The path, url or "-" (stdin) given on the command line.
+	Path   string // Local file holding the package, empty until fetched.
+	Name   string // Filename under which the package is stored in Pulp.
+	Sha256 string // Expected checksum of a fetched package, if known.
+	Temp   bool   // Path is a temporary file that must be removed afterwards.
+}
+
+type PackageUpload struct {
+	File    string
+	Sha256  string
+	Nevra   string
+	Signer  string
+	Status  string
+	Content []string
+	Err     error
 }
 
 type RepoPublicationList struct {
@@ -25,15 +83,103 @@ type RepoDetails struct {
 
 type PackageDetails struct {
 	Name    string `json:"name"`
+	Epoch   string `json:"epoch"`
 	Version string `json:"version"`
 	Release string `json:"release"`
 	Arch    string `json:"arch"`
 }
 
+type RpmHeaderEntry struct {
+	Tag    uint32
+	Type   uint32
+	Offset uint32
+	Count  uint32
+}
+
+type RpmHeader struct {
+	Raw   []byte // The complete header as found in the package.
+	Index []RpmHeaderEntry
+	Data  []byte
+}
+
+type RpmPackage struct {
+	Name      string
+	Epoch     string
+	Version   string
+	Release   string
+	Arch      string
+	SourceRpm string
+	PkgId     string // sha256 checksum of the package file.
+	Sha512    string
+	Source    bool
+	Size      int64
+	Payload   int64 // Offset of the payload in the package file.
+	Signature RpmHeader
+	Header    RpmHeader
+	DigestErr error // Set when the digests of a fully read package don't match.
+}
+
+type UploadOptions struct {
+	Version string       // Latest version of the repository.
+	Policy  string       // Signature policy.
+	Keyring []PgpKey     // Trusted keys.
+	Repo    *RepoDetails // Nil when the repository name can't be parsed.
+	Force   bool         // Only warn about integrity and policy problems.
+}
+
+type RepoConfig struct {
+	SignaturePolicy string      `json:"signature_policy,omitempty"`
+	RetainPackages  int         `json:"retain_packages,omitempty"`
+	Publish         *PublishSet `json:"publish,omitempty"`
+}
+
 type Configuration struct {
-	User string `json:"user"`
-	Pass string `json:"pass"`
-	Url  string `json:"url"`
+	User            string                `json:"user"`
+	Pass            string                `json:"pass"`
+	Url             string                `json:"url"`
+	ChunkSize       int64                 `json:"chunk_size,omitempty"`
+	MaxThreads      int                   `json:"max_threads,omitempty"`
+	Keyring         []string              `json:"keyring,omitempty"`
+	SignaturePolicy string                `json:"signature_policy,omitempty"`
+	Repositories    map[string]RepoConfig `json:"repositories,omitempty"`
+}
+
+type PgpPacket struct {
+	Tag  int
+	Body []byte
+}
+
+type PgpKey struct {
+	KeyId       uint64
+	Fingerprint []byte
+	UserId      string
+	Algo        byte
+	Created     time.Time
+	Public      crypto.PublicKey
+}
+
+type PgpSignature struct {
+	Version    byte
+	SigType    byte
+	PubAlgo    byte
+	HashAlgo   byte
+	KeyId      uint64
+	Created    time.Time
+	Expires    time.Duration // Zero when the signature never expires.
+	KeyExpires time.Duration // Zero when the key never expires.
+	KeyFlags   []byte        // Nil when the key may be used for anything.
+	Embedded   []byte        // The primary key binding of a signing subkey.
+	Hashed     []byte        // The part of the signature packet that is hashed as well.
+	Left16     []byte
+	Material   []byte
+}
+
+type RpmSignature struct {
+	Signed bool
+	Known  bool
+	KeyId  string
+	UserId string
+	Err    error
 }
 
 type Artifact struct {
@@ -57,16 +203,231 @@ type RemoveContentUnits struct {
 	Remove_content_units []string `json:"remove_content_units"`
 }
 
-type RepoSet struct {
-	Repository_version string `json:"repository_version"`
+type AdvisoryPackage struct {
+	Name             string `json:"name"`
+	Epoch            string `json:"epoch"`
+	Version          string `json:"version"`
+	Release          string `json:"release"`
+	Arch             string `json:"arch"`
+	Src              string `json:"src"`
+	Filename         string `json:"filename"`
+	Sum              string `json:"sum"`
+	Sum_type         string `json:"sum_type"`
+	Reboot_suggested bool   `json:"reboot_suggested"`
+}
+
+type AdvisoryCollection struct {
+	Name      string            `json:"name"`
+	Shortname string            `json:"shortname"`
+	Packages  []AdvisoryPackage `json:"packages"`
+}
+
+type AdvisoryReference struct {
+	Href     string `json:"href"`
+	Ref_id   string `json:"ref_id"`
+	Title    string `json:"title"`
+	Ref_type string `json:"ref_type"`
+}
+
+type Advisory struct {
+	Pulp_href        string               `json:"pulp_href,omitempty"`
+	Id               string               `json:"id"`
+	Updated_date     string               `json:"updated_date"`
+	Issued_date      string               `json:"issued_date"`
+	Description      string               `json:"description"`
+	Fromstr          string               `json:"fromstr"`
+	Status           string               `json:"status"`
+	Title            string               `json:"title"`
+	Summary          string               `json:"summary"`
+	Version          string               `json:"version"`
+	Type             string               `json:"type"`
+	Severity         string               `json:"severity"`
+	Solution         string               `json:"solution"`
+	Release          string               `json:"release"`
+	Rights           string               `json:"rights"`
+	Pushcount        string               `json:"pushcount"`
+	Reboot_suggested bool                 `json:"reboot_suggested"`
+	Pkglist          []AdvisoryCollection `json:"pkglist"`
+	References       []AdvisoryReference  `json:"references"`
+}
+
+type UpdateInfoDate struct {
+	Date string `xml:"date,attr"`
+}
+
+type UpdateInfoSum struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type UpdateInfoPackage struct {
+	Name             string        `xml:"name,attr"`
+	Epoch            string        `xml:"epoch,attr"`
+	Version          string        `xml:"version,attr"`
+	Release          string        `xml:"release,attr"`
+	Arch             string        `xml:"arch,attr"`
+	Src              string        `xml:"src,attr"`
+	Filename         string        `xml:"filename"`
+	Sum              UpdateInfoSum `xml:"sum"`
+	Reboot_suggested *string       `xml:"reboot_suggested"`
+}
+
+type UpdateInfoCollection struct {
+	Short    string              `xml:"short,attr"`
+	Name     string              `xml:"name"`
+	Packages []UpdateInfoPackage `xml:"package"`
+}
+
+type UpdateInfoReference struct {
+	Href  string `xml:"href,attr"`
+	Id    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type UpdateInfoUpdate struct {
+	From             string                 `xml:"from,attr"`
+	Status           string                 `xml:"status,attr"`
+	Type             string                 `xml:"type,attr"`
+	Version          string                 `xml:"version,attr"`
+	Id               string                 `xml:"id"`
+	Title            string                 `xml:"title"`
+	Issued           UpdateInfoDate         `xml:"issued"`
+	Updated          UpdateInfoDate         `xml:"updated"`
+	Rights           string                 `xml:"rights"`
+	Release          string                 `xml:"release"`
+	Pushcount        string                 `xml:"pushcount"`
+	Severity         string                 `xml:"severity"`
+	Summary          string                 `xml:"summary"`
+	Description      string                 `xml:"description"`
+	Solution         string                 `xml:"solution"`
+	Reboot_suggested *string                `xml:"reboot_suggested"`
+	References       []UpdateInfoReference  `xml:"references>reference"`
+	Collections      []UpdateInfoCollection `xml:"pkglist>collection"`
+}
+
+type UpdateInfo struct {
+	Updates []UpdateInfoUpdate `xml:"update"`
+}
+
+type SearchLocation struct {
+	Repository   string
+	Versions     []int
+	Latest       bool
+	Distribution []string
+}
+
+type PackageFilter struct {
+	Kind   string
+	Name   string
+	Arch   string
+	Newest bool
+}
+
+type PackageDiff struct {
+	Change string `json:"change"`
+	Name   string `json:"name"`
+	Arch   string `json:"arch"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+type DiffResult struct {
+	From    string        `json:"from"`
+	To      string        `json:"to"`
+	Changes []PackageDiff `json:"changes"`
+}
+
+type CopyConfig struct {
+	Source_repo_version string   `json:"source_repo_version"`
+	Dest_repo           string   `json:"dest_repo"`
+	Content             []string `json:"content"`
+}
+
+type CopySet struct {
+	Config             []CopyConfig `json:"config"`
+	Dependency_solving bool         `json:"dependency_solving"`
+}
+
+type PublishSet struct {
+	Repository_version     string `json:"repository_version,omitempty"`
+	Metadata_checksum_type string `json:"metadata_checksum_type,omitempty"`
+	Package_checksum_type  string `json:"package_checksum_type,omitempty"`
+	Gpgcheck               *int   `json:"gpgcheck,omitempty"`
+	Repo_gpgcheck          *int   `json:"repo_gpgcheck,omitempty"`
+	Sqlite_metadata        *bool  `json:"sqlite_metadata,omitempty"`
+}
+
+type PublishFlags struct {
+	Checksum         *string
+	MetadataChecksum *string
+	PackageChecksum  *string
+	Gpgcheck         *int
+	RepoGpgcheck     *int
+	Sqlite           *bool
+}
+
+type RepoRetainSet struct {
+	Retain_package_versions int `json:"retain_package_versions"`
+}
+
+type OrphanCleanupSet struct {
+	Content_hrefs          []string `json:"content_hrefs"`
+	Orphan_protection_time int      `json:"orphan_protection_time"`
 }
 
 type DistroSet struct {
-	Base_path     string `json:"base_path"`
-	Content_guard string `json:"content_guard"`
-	//Pulp_labels   PulpLabels `json:"pulp_labels"`
+	Base_path     string     `json:"base_path"`
+	Content_guard string     `json:"content_guard"`
+	Pulp_labels   PulpLabels `json:"pulp_labels,omitempty"`
+	Name          string     `json:"name"`
+	Publication   string     `json:"publication,omitempty"`
+}
+
+type LabelSet struct {
+	Pulp_labels PulpLabels `json:"pulp_labels"`
+}
+
+type RepositorySet struct {
 	Name        string `json:"name"`
-	Publication string `json:"publication"`
+	Description string `json:"description,omitempty"`
+	Remote      string `json:"remote,omitempty"`
+}
+
+type RemoteSet struct {
+	Name                 *string `json:"name,omitempty"`
+	Url                  *string `json:"url,omitempty"`
+	Policy               *string `json:"policy,omitempty"`
+	Ca_cert              *string `json:"ca_cert,omitempty"`
+	Client_cert          *string `json:"client_cert,omitempty"`
+	Client_key           *string `json:"client_key,omitempty"`
+	Tls_validation       *bool   `json:"tls_validation,omitempty"`
+	Username             *string `json:"username,omitempty"`
+	Password             *string `json:"password,omitempty"`
+	Proxy_url            *string `json:"proxy_url,omitempty"`
+	Proxy_username       *string `json:"proxy_username,omitempty"`
+	Proxy_password       *string `json:"proxy_password,omitempty"`
+	Rate_limit           *int    `json:"rate_limit,omitempty"`
+	Download_concurrency *int    `json:"download_concurrency,omitempty"`
+}
+
+type RemoteFlags struct {
+	Policy        *string
+	CaCert        *string
+	ClientCert    *string
+	ClientKey     *string
+	TlsValidation *bool
+	Username      *string
+	Password      *string
+	Proxy         *string
+	ProxyUsername *string
+	ProxyPassword *string
+	RateLimit     *int
+	Concurrency   *int
+}
+
+type RepoRemoteSet struct {
+	Remote *string `json:"remote"`
 }
 
 type SyncSet struct {
@@ -116,8 +477,7 @@ type PulpError struct {
 	Description string `json:"description"`
 }
 
-type PulpLabels struct {
-}
+type PulpLabels map[string]string
 
 type PulpCreate struct {
 	Pulp_href    string `json:"pulp_href"`
@@ -145,14 +505,39 @@ type PulpPublish struct {
 }
 
 type PulpRepository struct {
-	Pulp_href           string     `json:"pulp_href"`
-	Pulp_created        string     `json:"pulp_created"`
-	Versions_href       string     `json:"versions_href"`
-	Pulp_labels         PulpLabels `json:"pulp_labels"`
-	Latest_version_href string     `json:"latest_version_href"`
-	Name                string     `json:"name"`
-	Description         string     `json:"description"`
-	Remote              string     `json:"remote"`
+	Pulp_href               string     `json:"pulp_href"`
+	Pulp_created            string     `json:"pulp_created"`
+	Versions_href           string     `json:"versions_href"`
+	Pulp_labels             PulpLabels `json:"pulp_labels"`
+	Latest_version_href     string     `json:"latest_version_href"`
+	Name                    string     `json:"name"`
+	Description             string     `json:"description"`
+	Remote                  string     `json:"remote"`
+	Retain_package_versions int        `json:"retain_package_versions"`
+	Retain_repo_versions    int        `json:"retain_repo_versions"`
+}
+
+type PulpRepoVersion struct {
+	Pulp_href    string `json:"pulp_href"`
+	Pulp_created string `json:"pulp_created"`
+	Number       int    `json:"number"`
+	Repository   string `json:"repository"`
+}
+
+type PulpRemote struct {
+	Pulp_href            string     `json:"pulp_href"`
+	Pulp_created         string     `json:"pulp_created"`
+	Pulp_last_updated    string     `json:"pulp_last_updated"`
+	Pulp_labels          PulpLabels `json:"pulp_labels"`
+	Name                 string     `json:"name"`
+	Url                  string     `json:"url"`
+	Policy               string     `json:"policy"`
+	Ca_cert              string     `json:"ca_cert"`
+	Client_cert          string     `json:"client_cert"`
+	Tls_validation       bool       `json:"tls_validation"`
+	Proxy_url            string     `json:"proxy_url"`
+	Rate_limit           int        `json:"rate_limit"`
+	Download_concurrency int        `json:"download_concurrency"`
 }
 
 type PulpDistribution struct {
@@ -188,9 +573,9 @@ type PulpContent struct {
 	Description   string `json:"description"`
 	Url           string `json:"url"`
 	//Changelogs       []string        `json:"changelogs"`
-	//Files            [][]string      `json:"files"`
+	Files [][]string `json:"files"`
 	//Requires         [][]interface{} `json:"requires"`
-	//Provides         [][]interface{} `json:"provides"`
+	Provides [][]interface{} `json:"provides"`
 	//Conflicts        [][]interface{} `json:"conflicts"`
 	//Obsoletes        [][]interface{} `json:"obsoletes"`
 	//Suggests         [][]interface{} `json:"suggests"`
@@ -215,11 +600,17 @@ type PulpContent struct {
 	Time_file        int    `json:"time_file"`
 }
 
+type PulpUploadChunk struct {
+	Offset int64 `json:"offset"`
+	Size   int64 `json:"size"`
+}
+
 type PulpUploadResults struct {
-	Pulp_href    string `json:"pulp_href"`
-	Pulp_created string `json:"pulp_created"`
-	Size         int64  `json:"size"`
-	Completed    string `json:"completed"`
+	Pulp_href    string            `json:"pulp_href"`
+	Pulp_created string            `json:"pulp_created"`
+	Size         int64             `json:"size"`
+	Completed    string            `json:"completed"`
+	Chunks       []PulpUploadChunk `json:"chunks"`
 }
 
 type PulpCreateResults struct {
@@ -243,6 +634,20 @@ type PulpRepositoryResults struct {
 	Results  []PulpRepository `json:"results"`
 }
 
+type PulpRepoVersionResults struct {
+	Count    int               `json:"count"`
+	Next     string            `json:"next"`
+	Previous string            `json:"previous"`
+	Results  []PulpRepoVersion `json:"results"`
+}
+
+type PulpRemoteResults struct {
+	Count    int          `json:"count"`
+	Next     string       `json:"next"`
+	Previous string       `json:"previous"`
+	Results  []PulpRemote `json:"results"`
+}
+
 type PulpDistributionResults struct {
 	Count    int                `json:"count"`
 	Next     string             `json:"next"`
@@ -256,3 +661,36 @@ type PulpContentResults struct {
 	Previous string        `json:"previous"`
 	Results  []PulpContent `json:"results"`
 }
+
+type PulpGroupPackage struct {
+	Name string `json:"name"`
+}
+
+type PulpGroupId struct {
+	Name    string `json:"name"`
+	Default bool   `json:"default"`
+}
+
+type PulpComps struct {
+	Pulp_href   string             `json:"pulp_href"`
+	Id          string             `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Packages    []PulpGroupPackage `json:"packages"`
+	Group_ids   []PulpGroupId      `json:"group_ids"`
+	Option_ids  []PulpGroupId      `json:"option_ids"`
+}
+
+type PulpCompsResults struct {
+	Count    int         `json:"count"`
+	Next     string      `json:"next"`
+	Previous string      `json:"previous"`
+	Results  []PulpComps `json:"results"`
+}
+
+type PulpAdvisoryResults struct {
+	Count    int        `json:"count"`
+	Next     string     `json:"next"`
+	Previous string     `json:"previous"`
+	Results  []Advisory `json:"results"`
+}